@@ -0,0 +1,470 @@
+package gosql
+
+import (
+	"fmt"
+)
+
+// ComparisonExpr represents comparison of two values like "lhs = rhs".
+type ComparisonExpr interface {
+	BoolExpr
+	// Operator returns comparison operator like "=" or "<>".
+	Operator() string
+	// Left returns left operand.
+	Left() Value
+	// Right returns right operand.
+	Right() Value
+}
+
+// LogicalExpr represents logical expression like "lhs AND rhs".
+type LogicalExpr interface {
+	BoolExpr
+	// Operator returns logical operator: "AND" or "OR".
+	Operator() string
+	// Left returns left operand.
+	Left() BoolExpr
+	// Right returns right operand.
+	Right() BoolExpr
+}
+
+// InExpr represents expression like "lhs IN (values...)".
+type InExpr interface {
+	BoolExpr
+	// Left returns left operand.
+	Left() Value
+	// Values returns list of values.
+	Values() []Value
+	// Not returns true for "NOT IN" expression.
+	Not() bool
+}
+
+// ValueExpr represents value that is passed as query argument.
+type ValueExpr interface {
+	Value
+	// Value returns wrapped value.
+	Value() any
+}
+
+// FuncExpr represents function call expression.
+type FuncExpr interface {
+	Value
+	// Name returns name of function.
+	Name() string
+	// Args returns list of function arguments.
+	Args() []Value
+}
+
+// Test expressions for interfaces.
+var (
+	_ ComparisonExpr = cmp{}
+	_ LogicalExpr    = binaryExpr{}
+	_ InExpr         = inExpr{}
+	_ ValueExpr      = value{}
+	_ FuncExpr       = funcExpr{}
+)
+
+// Walk traverses query or expression in depth-first order.
+//
+// Function fn is called for each node and children of node are visited
+// only when fn returns true.
+func Walk(node any, fn func(node any) bool) {
+	if !fn(node) {
+		return
+	}
+	var children []Expr
+	switch v := node.(type) {
+	case queryWrapper:
+		Walk(v.unwrap(), fn)
+		return
+	case queryNode:
+		children = v.children()
+	case exprNode:
+		children = v.children()
+	}
+	for _, child := range children {
+		if child != nil {
+			Walk(child, fn)
+		}
+	}
+}
+
+// Rewrite returns copy of expression where each node is replaced by
+// result of fn.
+//
+// Children are rewritten before their parents, so fn receives node
+// with already rewritten children.
+func Rewrite(expr Expr, fn func(Expr) Expr) Expr {
+	if expr == nil {
+		return nil
+	}
+	if node, ok := expr.(exprNode); ok {
+		children := node.children()
+		if len(children) > 0 {
+			rewritten := make([]Expr, len(children))
+			for i, child := range children {
+				rewritten[i] = Rewrite(child, fn)
+			}
+			expr = node.withChildren(rewritten)
+		}
+	}
+	return fn(expr)
+}
+
+// RewriteQuery returns copy of query where each expression is rewritten
+// using Rewrite.
+//
+// Queries of unknown types are returned as is.
+func RewriteQuery(query Query, fn func(Expr) Expr) Query {
+	switch q := query.(type) {
+	case queryWrapper:
+		return q.wrap(RewriteQuery(q.unwrap(), fn))
+	case queryNode:
+		return q.rewrite(fn)
+	default:
+		return query
+	}
+}
+
+// queryNode represents query that contains expressions.
+type queryNode interface {
+	children() []Expr
+	rewrite(fn func(Expr) Expr) Query
+}
+
+// exprNode represents expression that contains other expressions.
+type exprNode interface {
+	children() []Expr
+	withChildren(children []Expr) Expr
+}
+
+func (q selectQuery) children() []Expr {
+	var children []Expr
+	children = append(children, q.names...)
+	if q.where != nil {
+		children = append(children, q.where)
+	}
+	for _, expr := range q.orderBy {
+		children = append(children, expr)
+	}
+	return children
+}
+
+func (q selectQuery) rewrite(fn func(Expr) Expr) Query {
+	clone := q.Clone().(*selectQuery)
+	for i, expr := range clone.names {
+		clone.names[i] = Rewrite(expr, fn)
+	}
+	clone.where = rewriteBool(clone.where, fn)
+	for i, expr := range clone.orderBy {
+		clone.orderBy[i] = asOrderExpr(Rewrite(expr, fn))
+	}
+	return clone
+}
+
+func (q updateQuery) children() []Expr {
+	var children []Expr
+	for _, value := range q.values {
+		children = append(children, value)
+	}
+	if q.where != nil {
+		children = append(children, q.where)
+	}
+	return children
+}
+
+func (q updateQuery) rewrite(fn func(Expr) Expr) Query {
+	clone := q.Clone().(*updateQuery)
+	for i, value := range clone.values {
+		clone.values[i] = asValue(Rewrite(value, fn))
+	}
+	clone.where = rewriteBool(clone.where, fn)
+	return clone
+}
+
+func (q deleteQuery) children() []Expr {
+	if q.where == nil {
+		return nil
+	}
+	return []Expr{q.where}
+}
+
+func (q deleteQuery) rewrite(fn func(Expr) Expr) Query {
+	clone := q.Clone().(*deleteQuery)
+	clone.where = rewriteBool(clone.where, fn)
+	return clone
+}
+
+func (q insertQuery) children() []Expr {
+	var children []Expr
+	for _, value := range q.values {
+		children = append(children, value)
+	}
+	return children
+}
+
+func (q insertQuery) rewrite(fn func(Expr) Expr) Query {
+	clone := q.clone()
+	clone.rewriteValues(fn)
+	return &clone
+}
+
+func (q *insertQuery) rewriteValues(fn func(Expr) Expr) {
+	for i, value := range q.values {
+		q.values[i] = asValue(Rewrite(value, fn))
+	}
+}
+
+func (q PostgresInsertQuery) rewrite(fn func(Expr) Expr) Query {
+	clone := q.Clone().(*PostgresInsertQuery)
+	clone.rewriteValues(fn)
+	return clone
+}
+
+// Operator returns logical operator: "AND" or "OR".
+func (e binaryExpr) Operator() string {
+	switch e.kind {
+	case orExpr:
+		return "OR"
+	case andExpr:
+		return "AND"
+	default:
+		panic(fmt.Errorf("unsupported binary expression: %d", e.kind))
+	}
+}
+
+// Left returns left operand.
+func (e binaryExpr) Left() BoolExpr {
+	return e.lhs
+}
+
+// Right returns right operand.
+func (e binaryExpr) Right() BoolExpr {
+	return e.rhs
+}
+
+func (e binaryExpr) children() []Expr {
+	return []Expr{e.lhs, e.rhs}
+}
+
+func (e binaryExpr) withChildren(children []Expr) Expr {
+	e.lhs, e.rhs = asBoolExpr(children[0]), asBoolExpr(children[1])
+	return e
+}
+
+// Operator returns comparison operator like "=" or "<>".
+func (c cmp) Operator() string {
+	switch c.kind {
+	case eqCmp:
+		return "="
+	case notEqCmp:
+		return "<>"
+	case lessCmp:
+		return "<"
+	case greaterCmp:
+		return ">"
+	case lessEqualCmp:
+		return "<="
+	case greaterEqualCmp:
+		return ">="
+	default:
+		panic(fmt.Errorf("unsupported binaryExpr %q", c.kind))
+	}
+}
+
+// Left returns left operand.
+func (c cmp) Left() Value {
+	return c.lhs
+}
+
+// Right returns right operand.
+func (c cmp) Right() Value {
+	return c.rhs
+}
+
+func (c cmp) children() []Expr {
+	return []Expr{c.lhs, c.rhs}
+}
+
+func (c cmp) withChildren(children []Expr) Expr {
+	c.lhs, c.rhs = asValue(children[0]), asValue(children[1])
+	return c
+}
+
+// Left returns left operand.
+func (e inExpr) Left() Value {
+	return e.lhs
+}
+
+// Values returns list of values.
+func (e inExpr) Values() []Value {
+	return append([]Value(nil), e.values...)
+}
+
+// Not returns true for "NOT IN" expression.
+func (e inExpr) Not() bool {
+	return e.not
+}
+
+func (e inExpr) children() []Expr {
+	children := []Expr{e.lhs}
+	for _, value := range e.values {
+		children = append(children, value)
+	}
+	return children
+}
+
+func (e inExpr) withChildren(children []Expr) Expr {
+	e.lhs = asValue(children[0])
+	e.values = make([]Value, len(children)-1)
+	for i, child := range children[1:] {
+		e.values[i] = asValue(child)
+	}
+	return e
+}
+
+// Value returns wrapped value.
+func (v value) Value() any {
+	return v.value
+}
+
+func (e order) children() []Expr {
+	return []Expr{e.expr}
+}
+
+func (e order) withChildren(children []Expr) Expr {
+	e.expr = children[0]
+	return e
+}
+
+func (e alias) children() []Expr {
+	return []Expr{e.expr}
+}
+
+func (e alias) withChildren(children []Expr) Expr {
+	e.expr = children[0]
+	return e
+}
+
+// Name returns name of function.
+func (e funcExpr) Name() string {
+	return e.name
+}
+
+// Args returns list of function arguments.
+func (e funcExpr) Args() []Value {
+	return append([]Value(nil), e.args...)
+}
+
+func (e funcExpr) children() []Expr {
+	var children []Expr
+	for _, arg := range e.args {
+		children = append(children, arg)
+	}
+	return children
+}
+
+func (e funcExpr) withChildren(children []Expr) Expr {
+	e.args = make([]Value, len(children))
+	for i, child := range children {
+		e.args[i] = asValue(child)
+	}
+	return e
+}
+
+func (e caseExpr) children() []Expr {
+	var children []Expr
+	children = append(children, e.operand)
+	for _, when := range e.whens {
+		children = append(children, when.cond, when.result)
+	}
+	children = append(children, e.orElse)
+	return children
+}
+
+func (e caseExpr) withChildren(children []Expr) Expr {
+	if children[0] != nil {
+		e.operand = asValue(children[0])
+	}
+	e.whens = make([]caseWhen, len(e.whens))
+	for i := range e.whens {
+		e.whens[i] = caseWhen{
+			cond:   children[2*i+1],
+			result: asValue(children[2*i+2]),
+		}
+	}
+	if last := children[len(children)-1]; last != nil {
+		e.orElse = asValue(last)
+	}
+	return e
+}
+
+func (e castExpr) children() []Expr {
+	return []Expr{e.expr}
+}
+
+func (e castExpr) withChildren(children []Expr) Expr {
+	e.expr = asValue(children[0])
+	return e
+}
+
+// Query returns query of raw fragment.
+func (e RawExpr) Query() string {
+	return e.query
+}
+
+// Args returns arguments of raw fragment.
+func (e RawExpr) Args() []any {
+	return append([]any(nil), e.args...)
+}
+
+func (e RawExpr) children() []Expr {
+	var children []Expr
+	for _, arg := range e.args {
+		if expr, ok := arg.(Expr); ok {
+			children = append(children, expr)
+		}
+	}
+	return children
+}
+
+func (e RawExpr) withChildren(children []Expr) Expr {
+	args := make([]any, len(e.args))
+	for i, arg := range e.args {
+		if _, ok := arg.(Expr); ok {
+			arg, children = children[0], children[1:]
+		}
+		args[i] = arg
+	}
+	e.args = args
+	return e
+}
+
+func rewriteBool(expr BoolExpr, fn func(Expr) Expr) BoolExpr {
+	if expr == nil {
+		return nil
+	}
+	return asBoolExpr(Rewrite(expr, fn))
+}
+
+func asValue(expr Expr) Value {
+	v, ok := expr.(Value)
+	if !ok {
+		panic(fmt.Errorf("expected value but got %T", expr))
+	}
+	return v
+}
+
+func asBoolExpr(expr Expr) BoolExpr {
+	v, ok := expr.(BoolExpr)
+	if !ok {
+		panic(fmt.Errorf("expected boolean expression but got %T", expr))
+	}
+	return v
+}
+
+func asOrderExpr(expr Expr) OrderExpr {
+	v, ok := expr.(OrderExpr)
+	if !ok {
+		panic(fmt.Errorf("expected order expression but got %T", expr))
+	}
+	return v
+}
@@ -0,0 +1,100 @@
+package gosql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	b := NewBuilder(SQLiteDialect)
+	where := Column("c1").Equal(1).
+		And(Lower(Column("c2")).In("a", "b")).
+		Or(Raw("? > ?", Column("c3"), 5))
+	q := b.Select("t1").
+		SetExprs("c1", As(Case().When(Column("c4").Equal(1), "x").Else(Cast(Column("c5"), TextType)), "c6")).
+		SetWhere(where).
+		SetOrderBy(Descending("c7"))
+	var columns []string
+	var values []any
+	var tables []string
+	Walk(q, func(node any) bool {
+		switch v := node.(type) {
+		case SelectQuery:
+			tables = append(tables, v.Table())
+		case Column:
+			columns = append(columns, string(v))
+		case ValueExpr:
+			values = append(values, v.Value())
+		}
+		return true
+	})
+	if expected := []string{"t1"}; !reflect.DeepEqual(tables, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tables)
+	}
+	if expected := []string{"c1", "c4", "c5", "c1", "c2", "c3", "c7"}; !reflect.DeepEqual(columns, expected) {
+		t.Fatalf("Expected %v, got %v", expected, columns)
+	}
+	if expected := []any{1, "x", 1, "a", "b"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("Expected %v, got %v", expected, values)
+	}
+	var operators []string
+	Walk(where, func(node any) bool {
+		switch v := node.(type) {
+		case LogicalExpr:
+			operators = append(operators, v.Operator())
+		case ComparisonExpr:
+			operators = append(operators, v.Operator())
+			return false
+		}
+		return true
+	})
+	if expected := []string{"OR", "AND", "="}; !reflect.DeepEqual(operators, expected) {
+		t.Fatalf("Expected %v, got %v", expected, operators)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	b := NewBuilder(SQLiteDialect)
+	prefix := func(expr Expr) Expr {
+		if c, ok := expr.(Column); ok {
+			return Column("t1." + string(c))
+		}
+		return expr
+	}
+	base := b.Select("t1").
+		SetExprs("c1", Coalesce(Column("c2"), 0)).
+		SetWhere(Column("c1").In(1, 2).Or(Raw("? IS NOT NULL", Column("c3")))).
+		SetOrderBy(Case(Column("c4")).When(1, Column("c5")))
+	q1 := RewriteQuery(base, prefix)
+	s1 := `SELECT "t1"."c1", COALESCE("t1"."c2", $1) FROM "t1" WHERE "t1"."c1" IN ($2, $3) OR ("t1"."c3" IS NOT NULL) ORDER BY CASE "t1"."c4" WHEN $4 THEN "t1"."c5" END ASC`
+	if s := b.BuildString(q1); s != s1 {
+		t.Fatalf("Expected %q, got %q", s1, s)
+	}
+	s2 := `SELECT "c1", COALESCE("c2", $1) FROM "t1" WHERE "c1" IN ($2, $3) OR ("c3" IS NOT NULL) ORDER BY CASE "c4" WHEN $4 THEN "c5" END ASC`
+	if s := b.BuildString(base); s != s2 {
+		t.Fatalf("Base query should not be changed: %q", s)
+	}
+	q3 := RewriteQuery(b.Update("t1").SetNames("c1").SetValues(Cast(Column("c2"), IntegerType)).SetWhere(Column("c3").Equal(1)), prefix)
+	s3 := `UPDATE "t1" SET "c1" = CAST("t1"."c2" AS INTEGER) WHERE "t1"."c3" = $1`
+	if s := b.BuildString(q3); s != s3 {
+		t.Fatalf("Expected %q, got %q", s3, s)
+	}
+	q4 := RewriteQuery(b.Delete("t1").SetWhere(Column("c1").NotEqual(nil)), prefix)
+	s4 := `DELETE FROM "t1" WHERE "t1"."c1" IS NOT NULL`
+	if s := b.BuildString(q4); s != s4 {
+		t.Fatalf("Expected %q, got %q", s4, s)
+	}
+	q5 := RewriteQuery(b.Insert("t1").SetNames("c1").SetValues(Column("c2")), prefix)
+	s5 := `INSERT INTO "t1" ("c1") VALUES ("t1"."c2")`
+	if s := b.BuildString(q5); s != s5 {
+		t.Fatalf("Expected %q, got %q", s5, s)
+	}
+	testExpectPanic(t, func() {
+		Rewrite(Column("c1").Equal(1), func(expr Expr) Expr {
+			if _, ok := expr.(Column); ok {
+				return Column("c1").Equal(2)
+			}
+			return expr
+		})
+	})
+}
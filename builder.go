@@ -2,6 +2,7 @@ package gosql
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -11,6 +12,8 @@ type Query interface {
 }
 
 // Builder represents SQL query builder.
+//
+// Table names can be qualified with schema name like "schema.table".
 type Builder interface {
 	// Dialect returns SQL dialect.
 	Dialect() Dialect
@@ -38,13 +41,54 @@ const (
 	PostgresDialect
 )
 
+// BuilderOption represents option for NewBuilder.
+type BuilderOption func(b *builder)
+
+// WithCastShorthand represents option that enables rendering of casts
+// as "expr::type" for Postgres dialect.
+func WithCastShorthand(enabled bool) BuilderOption {
+	return func(b *builder) {
+		b.castShorthand = enabled
+	}
+}
+
+// WithPlaceholderReuse represents option that enables reusing of
+// placeholders for identical parameters and scalar values for Postgres
+// dialect.
+//
+// Postgres deduces single type for each placeholder, so query that
+// compares the same parameter or value with columns of different types
+// can fail with "inconsistent types deduced for parameter" error.
+// Such queries should be built without this option.
+func WithPlaceholderReuse(enabled bool) BuilderOption {
+	return func(b *builder) {
+		b.placeholderReuse = enabled
+	}
+}
+
+// WithPrettyPrint represents option that enables formatting of query
+// with each clause on separate line.
+func WithPrettyPrint(enabled bool) BuilderOption {
+	return func(b *builder) {
+		b.prettyPrint = enabled
+	}
+}
+
 // NewBuilder creates a new instance of SQL builder.
-func NewBuilder(dialect Dialect) Builder {
-	return &builder{dialect: dialect}
+func NewBuilder(dialect Dialect, options ...BuilderOption) Builder {
+	b := builder{dialect: dialect}
+	for _, option := range options {
+		option(&b)
+	}
+	return &b
 }
 
 type builder struct {
-	dialect Dialect
+	dialect          Dialect
+	castShorthand    bool
+	placeholderReuse bool
+	redact           func(column string, value any) any
+	prettyPrint      bool
 }
 
 func (b builder) Dialect() Dialect {
@@ -52,28 +96,32 @@ func (b builder) Dialect() Dialect {
 }
 
 func (b *builder) Select(table string) SelectQuery {
-	return &selectQuery{table: table}
+	return &selectQuery{table: table, dialect: b.queryDialect()}
 }
 
 func (b *builder) Update(table string) UpdateQuery {
-	return &updateQuery{table: table}
+	return &updateQuery{table: table, dialect: b.queryDialect()}
 }
 
 func (b *builder) Delete(table string) DeleteQuery {
-	return &deleteQuery{table: table}
+	return &deleteQuery{table: table, dialect: b.queryDialect()}
 }
 
 func (b *builder) Insert(table string) InsertQuery {
 	switch b.dialect {
 	case PostgresDialect:
 		return &PostgresInsertQuery{
-			insertQuery: insertQuery{table: table},
+			insertQuery: insertQuery{table: table, dialect: b.queryDialect()},
 		}
 	default:
-		return &insertQuery{table: table}
+		return &insertQuery{table: table, dialect: b.queryDialect()}
 	}
 }
 
+func (b *builder) queryDialect() queryDialect {
+	return queryDialect{dialect: b.dialect, known: true}
+}
+
 func (b *builder) Build(query Query) (string, []any) {
 	builder := &writer{builder: b}
 	query.WriteQuery(builder)
@@ -85,8 +133,34 @@ func (b *builder) BuildString(query Query) string {
 	return str
 }
 
+func (b *builder) BuildInline(query Query) string {
+	builder := &writer{builder: b, inline: true}
+	builder.WriteString(inlineMarker)
+	query.WriteQuery(builder)
+	return builder.String()
+}
+
+// formatName formats possibly qualified name like "schema.table" or
+// "table.column" as sequence of quoted identifiers separated by dots.
 func (b builder) formatName(name string) string {
-	return fmt.Sprintf("%q", name)
+	var result strings.Builder
+	for i, part := range strings.Split(name, ".") {
+		if i > 0 {
+			result.WriteRune('.')
+		}
+		b.writeQuotedName(&result, part)
+	}
+	return result.String()
+}
+
+// writeQuotedName writes quoted identifier with escaped quote characters.
+//
+// Both SQLite and Postgres use standard SQL quoting where identifier is
+// enclosed in double quotes and each quote inside is doubled.
+func (b builder) writeQuotedName(w *strings.Builder, name string) {
+	w.WriteRune('"')
+	w.WriteString(strings.ReplaceAll(name, `"`, `""`))
+	w.WriteRune('"')
 }
 
 func (b builder) formatOpt(n int) string {
@@ -94,6 +168,11 @@ func (b builder) formatOpt(n int) string {
 }
 
 // Writer is used for building query string with specified values.
+//
+// Writers created by Builder also implement DialectWriter. Inline
+// rendering, fingerprints, pretty printing, cast shorthand and redaction
+// are supported only by writers created by Builder, other writers get
+// plain queries even when they implement DialectWriter.
 type Writer interface {
 	WriteRune(r rune)
 	WriteString(s string)
@@ -103,10 +182,75 @@ type Writer interface {
 	Values() []any
 }
 
+// DialectWriter represents writer that knows dialect of written query.
+type DialectWriter interface {
+	Writer
+	// Dialect returns SQL dialect.
+	Dialect() Dialect
+}
+
+// writerDialect returns dialect of writer if it implements DialectWriter.
+//
+// Queries created by Builder pass its dialect to writers without dialect,
+// other writers without dialect are treated as Postgres writers.
+func writerDialect(w Writer) (Dialect, bool) {
+	if dw, ok := w.(DialectWriter); ok {
+		return dw.Dialect(), true
+	}
+	return PostgresDialect, false
+}
+
 type writer struct {
 	builder *builder
 	query   strings.Builder
 	values  []any
+	// placeholders contains indexes of reusable values.
+	placeholders map[any]int
+	// inline specifies that values should be written as literals.
+	inline bool
+	// fingerprint specifies that values should be normalized.
+	fingerprint bool
+	// column contains name of column related to written value.
+	column string
+	// depth contains nesting level of boolean expressions.
+	depth int
+}
+
+// queryDialect contains dialect of builder that created query.
+type queryDialect struct {
+	dialect Dialect
+	known   bool
+}
+
+// writer returns writer with dialect of query when specified writer
+// does not implement DialectWriter.
+func (d queryDialect) writer(w Writer) Writer {
+	if !d.known {
+		return w
+	}
+	if _, ok := w.(DialectWriter); ok {
+		return w
+	}
+	return dialectWriter{Writer: w, dialect: d.dialect}
+}
+
+// dialectWriter represents writer with dialect of query.
+type dialectWriter struct {
+	Writer
+	dialect Dialect
+}
+
+// Dialect returns SQL dialect.
+func (w dialectWriter) Dialect() Dialect {
+	return w.dialect
+}
+
+// Test *writer for interfaces.
+var _ DialectWriter = &writer{}
+
+// Dialect returns SQL dialect.
+func (w *writer) Dialect() Dialect {
+	return w.builder.dialect
 }
 
 func (w *writer) WriteRune(r rune) {
@@ -122,10 +266,51 @@ func (w *writer) WriteName(name string) {
 }
 
 func (w *writer) WriteValue(value any) {
+	if w.inline {
+		w.writeInlineValue(value)
+		return
+	}
+	if w.fingerprint {
+		w.query.WriteRune('?')
+		return
+	}
+	if w.canReuse(value) {
+		if n, ok := w.placeholders[value]; ok {
+			w.query.WriteString(w.builder.formatOpt(n))
+			return
+		}
+		if w.placeholders == nil {
+			w.placeholders = map[any]int{}
+		}
+		w.placeholders[value] = len(w.values) + 1
+	}
 	w.values = append(w.values, value)
 	w.query.WriteString(w.builder.formatOpt(len(w.values)))
 }
 
+func (w *writer) canReuse(value any) bool {
+	if !w.builder.placeholderReuse || w.builder.dialect != PostgresDialect {
+		return false
+	}
+	if _, ok := value.(Param); ok {
+		return true
+	}
+	if value == nil {
+		return false
+	}
+	// Only scalar values are reused because they are always comparable
+	// and equality of such values means identity.
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
 func (w *writer) String() string {
 	return w.query.String()
 }
@@ -167,8 +352,12 @@ func (e binaryExpr) And(o BoolExpr) BoolExpr {
 }
 
 func (e binaryExpr) formatPart(w Writer, expr BoolExpr) {
-	if part, ok := expr.(binaryExpr); ok && part.kind != e.kind {
+	if e.needParens(expr) {
 		w.WriteRune('(')
+		if wr, ok := w.(*writer); ok {
+			wr.depth++
+			defer func() { wr.depth-- }()
+		}
 		expr.WriteExpr(w)
 		w.WriteRune(')')
 	} else {
@@ -176,13 +365,25 @@ func (e binaryExpr) formatPart(w Writer, expr BoolExpr) {
 	}
 }
 
+func (e binaryExpr) needParens(expr BoolExpr) bool {
+	switch part := expr.(type) {
+	case binaryExpr:
+		return part.kind != e.kind
+	case RawExpr:
+		// Raw fragment can contain operators with any precedence.
+		return true
+	default:
+		return false
+	}
+}
+
 func (e binaryExpr) WriteExpr(w Writer) {
 	e.formatPart(w, e.lhs)
 	switch e.kind {
 	case orExpr:
-		w.WriteString(" OR ")
+		writeOperator(w, "OR")
 	case andExpr:
-		w.WriteString(" AND ")
+		writeOperator(w, "AND")
 	default:
 		panic(fmt.Errorf("unsupported binary expression: %d", e.kind))
 	}
@@ -198,9 +399,13 @@ type Value interface {
 	Greater(any) BoolExpr
 	LessEqual(any) BoolExpr
 	GreaterEqual(any) BoolExpr
+	In(...any) BoolExpr
+	NotIn(...any) BoolExpr
 }
 
 // Column represents comparable table column.
+//
+// Column name can be qualified with table name like "table.column".
 type Column string
 
 // Equal build boolean expression: "column = value".
@@ -233,6 +438,16 @@ func (c Column) GreaterEqual(o any) BoolExpr {
 	return cmp{kind: greaterEqualCmp, lhs: c, rhs: wrapValue(o)}
 }
 
+// In build boolean expression: "column IN (values...)".
+func (c Column) In(o ...any) BoolExpr {
+	return newIn(c, false, o)
+}
+
+// NotIn build boolean expression: "column NOT IN (values...)".
+func (c Column) NotIn(o ...any) BoolExpr {
+	return newIn(c, true, o)
+}
+
 func (c Column) WriteExpr(w Writer) {
 	w.WriteName(string(c))
 }
@@ -265,6 +480,14 @@ func (v value) GreaterEqual(o any) BoolExpr {
 	return cmp{kind: greaterEqualCmp, lhs: v, rhs: wrapValue(o)}
 }
 
+func (v value) In(o ...any) BoolExpr {
+	return newIn(v, false, o)
+}
+
+func (v value) NotIn(o ...any) BoolExpr {
+	return newIn(v, true, o)
+}
+
 func (v value) WriteExpr(w Writer) {
 	w.WriteValue(v.value)
 }
@@ -329,6 +552,22 @@ func Descending(val any) OrderExpr {
 	}
 }
 
+type alias struct {
+	expr Expr
+	name string
+}
+
+// As build expression with alias: "expr AS name".
+func As(expr any, name string) Expr {
+	return alias{expr: wrapExpression(expr), name: name}
+}
+
+func (e alias) WriteExpr(w Writer) {
+	e.expr.WriteExpr(w)
+	w.WriteString(" AS ")
+	w.WriteName(e.name)
+}
+
 type cmpKind int
 
 const (
@@ -354,7 +593,7 @@ func (c cmp) And(o BoolExpr) BoolExpr {
 }
 
 func (c cmp) WriteExpr(w Writer) {
-	c.lhs.WriteExpr(w)
+	operand(c.lhs).WriteExpr(w)
 	switch c.kind {
 	case eqCmp:
 		if isNullValue(c.rhs) {
@@ -379,7 +618,89 @@ func (c cmp) WriteExpr(w Writer) {
 	default:
 		panic(fmt.Errorf("unsupported binaryExpr %q", c.kind))
 	}
-	c.rhs.WriteExpr(w)
+	if column, ok := c.lhs.(Column); ok {
+		writeColumnExpr(w, string(column), operand(c.rhs))
+	} else {
+		operand(c.rhs).WriteExpr(w)
+	}
+}
+
+type inExpr struct {
+	lhs    Value
+	not    bool
+	values []Value
+}
+
+func newIn(lhs Value, not bool, values []any) inExpr {
+	expr := inExpr{lhs: lhs, not: not}
+	for _, val := range values {
+		expr.values = append(expr.values, wrapValue(val))
+	}
+	return expr
+}
+
+func (e inExpr) Or(o BoolExpr) BoolExpr {
+	return binaryExpr{kind: orExpr, lhs: e, rhs: o}
+}
+
+func (e inExpr) And(o BoolExpr) BoolExpr {
+	return binaryExpr{kind: andExpr, lhs: e, rhs: o}
+}
+
+func (e inExpr) WriteExpr(w Writer) {
+	if len(e.values) == 0 {
+		// Empty list never contains any value.
+		if e.not {
+			w.WriteString("1 = 1")
+		} else {
+			w.WriteString("1 = 0")
+		}
+		return
+	}
+	operand(e.lhs).WriteExpr(w)
+	if e.not {
+		w.WriteString(" NOT IN (")
+	} else {
+		w.WriteString(" IN (")
+	}
+	if wr, ok := w.(*writer); ok && wr.fingerprint {
+		w.WriteString("...)")
+		return
+	}
+	column, isColumn := e.lhs.(Column)
+	for i, val := range e.values {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if isColumn {
+			writeColumnExpr(w, string(column), operand(val))
+		} else {
+			operand(val).WriteExpr(w)
+		}
+	}
+	w.WriteRune(')')
+}
+
+// parenExpr represents expression enclosed in parentheses.
+type parenExpr struct {
+	expr Expr
+}
+
+func (e parenExpr) WriteExpr(w Writer) {
+	w.WriteRune('(')
+	e.expr.WriteExpr(w)
+	w.WriteRune(')')
+}
+
+// operand returns operand of comparison or IN expression.
+//
+// Raw fragment can contain operators with any precedence, so it is
+// enclosed in parentheses.
+func operand(expr Expr) Expr {
+	if raw, ok := expr.(RawExpr); ok {
+		return parenExpr{expr: raw}
+	}
+	return expr
 }
 
 func isNullValue(val Value) bool {
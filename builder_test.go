@@ -1,76 +1,35 @@
 package gosql
 
 import (
+	"context"
+	"database/sql"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
-type testSetNamesImpl interface {
-	SetNames(names ...string)
-}
-
-func testSetNames[T testSetNamesImpl](query T, names ...string) T {
-	query.SetNames(names...)
-	return query
-}
-
-type testSetWhereImpl interface {
-	SetWhere(where BoolExpr)
-}
-
-func testSetWhere[T testSetWhereImpl](query T, where BoolExpr) T {
-	query.SetWhere(where)
-	return query
-}
-
-type testSetOrderByImpl interface {
-	SetOrderBy(names ...any)
-}
-
-func testSetOrderBy[T testSetOrderByImpl](query T, names ...any) T {
-	query.SetOrderBy(names...)
-	return query
-}
-
-type testSetLimitImpl interface {
-	SetLimit(limit int)
-}
-
-func testSetLimit[T testSetLimitImpl](query T, limit int) T {
-	query.SetLimit(limit)
-	return query
-}
-
-type testSetValuesImpl interface {
-	SetValues(values ...any)
-}
-
-func testSetValues[T testSetValuesImpl](query T, values ...any) T {
-	query.SetValues(values...)
-	return query
-}
-
 func TestSelectQuery(t *testing.T) {
 	b := NewBuilder(SQLiteDialect)
 	inputs := []SelectQuery{
 		b.Select("t1"),
-		testSetNames(b.Select("t1"), "c1", "c2", "c3"),
-		testSetWhere(b.Select("t1"), Column("c1").Equal(123)),
-		testSetWhere(b.Select("t1"), Column("c1").NotEqual(123)),
-		testSetWhere(b.Select("t1"), Column("c2").Equal(nil)),
-		testSetWhere(b.Select("t1"), Column("c2").NotEqual(nil)),
-		testSetWhere(b.Select("t1"), Column("c3").Less(0)),
-		testSetWhere(b.Select("t1"), Column("c3").Greater(0)),
-		testSetWhere(b.Select("t1"), Column("c3").LessEqual(0)),
-		testSetWhere(b.Select("t1"), Column("c3").GreaterEqual(0)),
-		testSetWhere(b.Select("t1"), Column("c1").Greater(0).And(Column("c1").LessEqual(100))),
-		testSetWhere(b.Select("t1"), Column("c1").Greater(0).Or(Column("c1").LessEqual(100))),
-		testSetOrderBy(b.Select("t1"), "c1", "c2"),
-		testSetOrderBy(b.Select("t1"), Descending("c1"), Descending(Ascending("c2")), Ascending(Descending("c3"))),
-		testSetWhere(b.Select("t1"), Column("c1").Greater(0).And(Column("c1").LessEqual(100)).Or(Column("c1").Less(-10))),
-		testSetWhere(b.Select("t1"), Column("c1").Greater(0).And(Column("c1").LessEqual(100)).And(Column("c1").Less(10))),
-		testSetWhere(b.Select("t1"), Column("c1").Greater(0).And(Column("c1").LessEqual(100).Or(Column("c1").Less(-10)))),
-		testSetLimit(testSetOrderBy(b.Select("t1"), "c1"), 123),
+		b.Select("t1").SetNames("c1", "c2", "c3"),
+		b.Select("t1").SetWhere(Column("c1").Equal(123)),
+		b.Select("t1").SetWhere(Column("c1").NotEqual(123)),
+		b.Select("t1").SetWhere(Column("c2").Equal(nil)),
+		b.Select("t1").SetWhere(Column("c2").NotEqual(nil)),
+		b.Select("t1").SetWhere(Column("c3").Less(0)),
+		b.Select("t1").SetWhere(Column("c3").Greater(0)),
+		b.Select("t1").SetWhere(Column("c3").LessEqual(0)),
+		b.Select("t1").SetWhere(Column("c3").GreaterEqual(0)),
+		b.Select("t1").SetWhere(Column("c1").Greater(0).And(Column("c1").LessEqual(100))),
+		b.Select("t1").SetWhere(Column("c1").Greater(0).Or(Column("c1").LessEqual(100))),
+		b.Select("t1").SetOrderBy("c1", "c2"),
+		b.Select("t1").SetOrderBy(Descending("c1"), Descending(Ascending("c2")), Ascending(Descending("c3"))),
+		b.Select("t1").SetWhere(Column("c1").Greater(0).And(Column("c1").LessEqual(100)).Or(Column("c1").Less(-10))),
+		b.Select("t1").SetWhere(Column("c1").Greater(0).And(Column("c1").LessEqual(100)).And(Column("c1").Less(10))),
+		b.Select("t1").SetWhere(Column("c1").Greater(0).And(Column("c1").LessEqual(100).Or(Column("c1").Less(-10)))),
+		b.Select("t1").SetOrderBy("c1").SetLimit(123),
 	}
 	outputs := []string{
 		`SELECT * FROM "t1" WHERE 1 = 1`,
@@ -102,7 +61,7 @@ func TestSelectQuery(t *testing.T) {
 
 func TestUpdateQuery(t *testing.T) {
 	b := NewBuilder(SQLiteDialect)
-	q1 := testSetValues(testSetNames(testSetWhere(b.Update("t1"), Column("c1").Equal(123)), "c2", "c3"), "test", "test2")
+	q1 := b.Update("t1").SetWhere(Column("c1").Equal(123)).SetNames("c2", "c3").SetValues("test", "test2")
 	s1 := `UPDATE "t1" SET "c2" = $1, "c3" = $2 WHERE "c1" = $3`
 	v1 := []any{"test", "test2", 123}
 	if s, v := b.Build(q1); s != s1 || !reflect.DeepEqual(v, v1) {
@@ -111,14 +70,14 @@ func TestUpdateQuery(t *testing.T) {
 	if s := b.BuildString(q1); s != s1 {
 		t.Fatalf("Expected %q got %q", s1, s)
 	}
-	q2 := testSetValues(testSetNames(b.Update("t2"), "c1", "c2"), "test", "test2")
+	q2 := b.Update("t2").SetNames("c1", "c2").SetValues("test", "test2")
 	s2 := `UPDATE "t2" SET "c1" = $1, "c2" = $2 WHERE 1 = 1`
 	v2 := []any{"test", "test2"}
 	if s, v := b.Build(q2); s != s2 || !reflect.DeepEqual(v, v2) {
 		t.Fatalf("Expected %q got %q", s2, s)
 	}
 	testExpectPanic(t, func() {
-		b.Build(testSetValues(testSetNames(b.Update("t1"), "c2", "c3"), "test"))
+		b.Build(b.Update("t1").SetNames("c2", "c3").SetValues("test"))
 	})
 	testExpectPanic(t, func() {
 		b.Build(b.Update("t1"))
@@ -127,7 +86,7 @@ func TestUpdateQuery(t *testing.T) {
 
 func TestDeleteQuery(t *testing.T) {
 	b := NewBuilder(SQLiteDialect)
-	q1 := testSetWhere(b.Delete("t1"), Column("c1").Equal(123))
+	q1 := b.Delete("t1").SetWhere(Column("c1").Equal(123))
 	s1 := `DELETE FROM "t1" WHERE "c1" = $1`
 	if s := b.BuildString(q1); s != s1 {
 		t.Fatalf("Expected %q got %q", s1, s)
@@ -141,7 +100,7 @@ func TestDeleteQuery(t *testing.T) {
 
 func TestInsertQuery(t *testing.T) {
 	b := NewBuilder(SQLiteDialect)
-	q1 := testSetValues(testSetNames(b.Insert("t1"), "c2", "c3"), "test", "test2")
+	q1 := b.Insert("t1").SetNames("c2", "c3").SetValues("test", "test2")
 	s1 := `INSERT INTO "t1" ("c2", "c3") VALUES ($1, $2)`
 	v1 := []any{"test", "test2"}
 	if s, v := b.Build(q1); s != s1 || !reflect.DeepEqual(v, v1) {
@@ -151,7 +110,7 @@ func TestInsertQuery(t *testing.T) {
 		t.Fatalf("Expected %q got %q", s1, s)
 	}
 	testExpectPanic(t, func() {
-		b.Build(testSetValues(testSetNames(b.Insert("t1"), "c2", "c3"), "test"))
+		b.Build(b.Insert("t1").SetNames("c2", "c3").SetValues("test"))
 	})
 	testExpectPanic(t, func() {
 		b.Build(b.Insert("t1"))
@@ -160,8 +119,8 @@ func TestInsertQuery(t *testing.T) {
 
 func TestPostgresInsertQuery(t *testing.T) {
 	b := NewBuilder(PostgresDialect)
-	q1 := testSetValues(testSetNames(b.Insert("t1"), "c2", "c3"), "test", "test2")
-	q1.(*PostgresInsertQuery).SetReturning("id")
+	q1 := b.Insert("t1").SetNames("c2", "c3").SetValues("test", "test2").
+		(*PostgresInsertQuery).SetReturning("id")
 	s1 := `INSERT INTO "t1" ("c2", "c3") VALUES ($1, $2) RETURNING "id"`
 	v1 := []any{"test", "test2"}
 	if s, v := b.Build(q1); s != s1 || !reflect.DeepEqual(v, v1) {
@@ -176,6 +135,532 @@ func TestPostgresInsertQuery(t *testing.T) {
 	})
 }
 
+func TestQuoteName(t *testing.T) {
+	b := NewBuilder(SQLiteDialect)
+	inputs := []Query{
+		b.Select("public.users"),
+		b.Select("t1").SetNames("t1.c1", `c"2`),
+		b.Select(`s"1.t"1`).SetWhere(Column("t1.c1").Equal(1)),
+		b.Select("t1").SetNames("Ёлка"),
+	}
+	outputs := []string{
+		`SELECT * FROM "public"."users" WHERE 1 = 1`,
+		`SELECT "t1"."c1", "c""2" FROM "t1" WHERE 1 = 1`,
+		`SELECT * FROM "s""1"."t""1" WHERE "t1"."c1" = $1`,
+		`SELECT "Ёлка" FROM "t1" WHERE 1 = 1`,
+	}
+	for i, input := range inputs {
+		query := b.BuildString(input)
+		if query != outputs[i] {
+			t.Errorf("Expected %q, got %q", outputs[i], query)
+		}
+	}
+}
+
+func TestFunc(t *testing.T) {
+	sqlite := NewBuilder(SQLiteDialect)
+	postgres := NewBuilder(PostgresDialect)
+	inputs := []BoolExpr{
+		Func("LENGTH", Column("c1")).Greater(10),
+		Coalesce(Column("c1"), "test").Equal(Lower(Column("c2"))),
+		Upper(Column("c1")).NotEqual(nil),
+		Abs(Column("c1")).LessEqual(Length(Column("c2"))),
+		Column("c1").Less(Now()),
+		StringAgg(Column("c1"), ",").Equal("a,b"),
+		Substring(Column("c1"), 1, 2).Equal("ab"),
+	}
+	sqliteOutputs := []string{
+		`SELECT * FROM "t1" WHERE LENGTH("c1") > $1`,
+		`SELECT * FROM "t1" WHERE COALESCE("c1", $1) = LOWER("c2")`,
+		`SELECT * FROM "t1" WHERE UPPER("c1") IS NOT NULL`,
+		`SELECT * FROM "t1" WHERE ABS("c1") <= LENGTH("c2")`,
+		`SELECT * FROM "t1" WHERE "c1" < CURRENT_TIMESTAMP`,
+		`SELECT * FROM "t1" WHERE GROUP_CONCAT("c1", $1) = $2`,
+		`SELECT * FROM "t1" WHERE SUBSTR("c1", $1, $2) = $3`,
+	}
+	postgresOutputs := []string{
+		`SELECT * FROM "t1" WHERE LENGTH("c1") > $1`,
+		`SELECT * FROM "t1" WHERE COALESCE("c1", $1) = LOWER("c2")`,
+		`SELECT * FROM "t1" WHERE UPPER("c1") IS NOT NULL`,
+		`SELECT * FROM "t1" WHERE ABS("c1") <= LENGTH("c2")`,
+		`SELECT * FROM "t1" WHERE "c1" < NOW()`,
+		`SELECT * FROM "t1" WHERE STRING_AGG("c1", $1) = $2`,
+		`SELECT * FROM "t1" WHERE SUBSTRING("c1", $1, $2) = $3`,
+	}
+	for i, input := range inputs {
+		if query := sqlite.BuildString(sqlite.Select("t1").SetWhere(input)); query != sqliteOutputs[i] {
+			t.Errorf("Expected %q, got %q", sqliteOutputs[i], query)
+		}
+		if query := postgres.BuildString(postgres.Select("t1").SetWhere(input)); query != postgresOutputs[i] {
+			t.Errorf("Expected %q, got %q", postgresOutputs[i], query)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	b := NewBuilder(SQLiteDialect)
+	status := Case().
+		When(Column("c1").Equal(1), "active").
+		When(Column("c1").Equal(2), "blocked").
+		Else("unknown")
+	inputs := []Query{
+		b.Select("t1").SetExprs("c1", As(status, "status")),
+		b.Select("t1").SetOrderBy(Descending(Case(Column("c1")).When(2, 0).Else(Column("c2")))),
+		b.Select("t1").SetWhere(Case().When(Column("c1").Less(0), Column("c2")).Equal(5)),
+		b.Update("t1").SetNames("c1").SetValues(Case(Column("c1")).When(1, 2).When(2, 1)),
+	}
+	outputs := []string{
+		`SELECT "c1", CASE WHEN "c1" = $1 THEN $2 WHEN "c1" = $3 THEN $4 ELSE $5 END AS "status" FROM "t1" WHERE 1 = 1`,
+		`SELECT * FROM "t1" WHERE 1 = 1 ORDER BY CASE "c1" WHEN $1 THEN $2 ELSE "c2" END DESC`,
+		`SELECT * FROM "t1" WHERE CASE WHEN "c1" < $1 THEN "c2" END = $2`,
+		`UPDATE "t1" SET "c1" = CASE "c1" WHEN $1 THEN $2 WHEN $3 THEN $4 END WHERE 1 = 1`,
+	}
+	for i, input := range inputs {
+		query := b.BuildString(input)
+		if query != outputs[i] {
+			t.Errorf("Expected %q, got %q", outputs[i], query)
+		}
+	}
+	base := Case().When(Column("c1").Equal(1), 1)
+	_ = base.When(Column("c1").Equal(2), 2)
+	if s := b.BuildString(b.Select("t1").SetExprs(base)); s != `SELECT CASE WHEN "c1" = $1 THEN $2 END FROM "t1" WHERE 1 = 1` {
+		t.Fatalf("Base expression should not be changed: %q", s)
+	}
+	testExpectPanic(t, func() {
+		b.Build(b.Select("t1").SetExprs(Case()))
+	})
+	testExpectPanic(t, func() {
+		Case(1, 2)
+	})
+}
+
+func TestCast(t *testing.T) {
+	sqlite := NewBuilder(SQLiteDialect)
+	postgres := NewBuilder(PostgresDialect)
+	shorthand := NewBuilder(PostgresDialect, WithCastShorthand(true))
+	inputs := []BoolExpr{
+		Cast(Column("c1"), IntegerType).Equal(Cast(1, BigIntType)),
+		Cast(Column("c1"), TextType).NotEqual(Cast(Column("c2"), UUIDType)),
+		Cast(Column("c1"), BooleanType).Equal(true),
+		Cast(Column("c1"), TimestampType).Less(Cast(Column("c2"), JSONType)),
+		Cast(Column("c1"), NumericType(10, 2)).Greater(Cast(Case().When(Column("c2").Equal(1), 2), IntegerType)),
+	}
+	sqliteOutputs := []string{
+		`SELECT * FROM "t1" WHERE CAST("c1" AS INTEGER) = CAST($1 AS INTEGER)`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS TEXT) <> CAST("c2" AS TEXT)`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS INTEGER) = $1`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS TEXT) < CAST("c2" AS TEXT)`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS NUMERIC) > CAST(CASE WHEN "c2" = $1 THEN $2 END AS INTEGER)`,
+	}
+	postgresOutputs := []string{
+		`SELECT * FROM "t1" WHERE CAST("c1" AS INTEGER) = CAST($1 AS BIGINT)`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS TEXT) <> CAST("c2" AS UUID)`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS BOOLEAN) = $1`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS TIMESTAMP) < CAST("c2" AS JSON)`,
+		`SELECT * FROM "t1" WHERE CAST("c1" AS NUMERIC(10, 2)) > CAST(CASE WHEN "c2" = $1 THEN $2 END AS INTEGER)`,
+	}
+	shorthandOutputs := []string{
+		`SELECT * FROM "t1" WHERE "c1"::INTEGER = $1::BIGINT`,
+		`SELECT * FROM "t1" WHERE "c1"::TEXT <> "c2"::UUID`,
+		`SELECT * FROM "t1" WHERE "c1"::BOOLEAN = $1`,
+		`SELECT * FROM "t1" WHERE "c1"::TIMESTAMP < "c2"::JSON`,
+		`SELECT * FROM "t1" WHERE "c1"::NUMERIC(10, 2) > (CASE WHEN "c2" = $1 THEN $2 END)::INTEGER`,
+	}
+	for i, input := range inputs {
+		if query := sqlite.BuildString(sqlite.Select("t1").SetWhere(input)); query != sqliteOutputs[i] {
+			t.Errorf("Expected %q, got %q", sqliteOutputs[i], query)
+		}
+		if query := postgres.BuildString(postgres.Select("t1").SetWhere(input)); query != postgresOutputs[i] {
+			t.Errorf("Expected %q, got %q", postgresOutputs[i], query)
+		}
+		if query := shorthand.BuildString(shorthand.Select("t1").SetWhere(input)); query != shorthandOutputs[i] {
+			t.Errorf("Expected %q, got %q", shorthandOutputs[i], query)
+		}
+	}
+}
+
+func TestRaw(t *testing.T) {
+	b := NewBuilder(PostgresDialect)
+	q1 := b.Select("t1").SetWhere(Column("c1").Equal(1).And(Raw("lower(?) = ?", Column("c2"), "test")))
+	s1 := `SELECT * FROM "t1" WHERE "c1" = $1 AND (lower("c2") = $2)`
+	v1 := []any{1, "test"}
+	if s, v := b.Build(q1); s != s1 || !reflect.DeepEqual(v, v1) {
+		t.Fatalf("Expected %q got %q", s1, s)
+	}
+	q2 := b.Select("t1").SetWhere(Raw("? ?? ?", Column("c1"), Raw("?::jsonb", "key")).Or(Raw("? > ?", Now(), 5)))
+	s2 := `SELECT * FROM "t1" WHERE ("c1" ? $1::jsonb) OR (NOW() > $2)`
+	v2 := []any{"key", 5}
+	if s, v := b.Build(q2); s != s2 || !reflect.DeepEqual(v, v2) {
+		t.Fatalf("Expected %q got %q", s2, s)
+	}
+	q3 := b.Select("t1").SetExprs(As(Raw("count(*)"), "cnt")).SetOrderBy(Raw("random()"))
+	s3 := `SELECT count(*) AS "cnt" FROM "t1" WHERE 1 = 1 ORDER BY random() ASC`
+	if s := b.BuildString(q3); s != s3 {
+		t.Fatalf("Expected %q got %q", s3, s)
+	}
+	q4 := b.Select("t1").SetWhere(Raw("length(?)", Column("c1")).Greater(3))
+	s4 := `SELECT * FROM "t1" WHERE (length("c1")) > $1`
+	if s := b.BuildString(q4); s != s4 {
+		t.Fatalf("Expected %q got %q", s4, s)
+	}
+	inputs := []BoolExpr{
+		Raw("a OR b").Equal(1),
+		Raw("a OR b").NotEqual(nil),
+		Column("c1").Less(Raw("? + 1", Column("c2"))),
+		Raw("a || b").In("x", Raw("c || d")),
+		Column("c1").NotIn(Raw("1 OR 2"), 3),
+	}
+	outputs := []string{
+		`SELECT * FROM "t1" WHERE (a OR b) = $1`,
+		`SELECT * FROM "t1" WHERE (a OR b) IS NOT NULL`,
+		`SELECT * FROM "t1" WHERE "c1" < ("c2" + 1)`,
+		`SELECT * FROM "t1" WHERE (a || b) IN ($1, (c || d))`,
+		`SELECT * FROM "t1" WHERE "c1" NOT IN ((1 OR 2), $1)`,
+	}
+	for i, input := range inputs {
+		if s := b.BuildString(b.Select("t1").SetWhere(input)); s != outputs[i] {
+			t.Errorf("Expected %q got %q", outputs[i], s)
+		}
+	}
+	testExpectPanic(t, func() {
+		b.Build(b.Select("t1").SetWhere(Raw("? = ?", 1)))
+	})
+	testExpectPanic(t, func() {
+		b.Build(b.Select("t1").SetWhere(Raw("?", 1, 2)))
+	})
+}
+
+func TestParam(t *testing.T) {
+	b := NewBuilder(PostgresDialect)
+	q1 := b.Select("t1").SetWhere(Column("c1").Equal(Param("id")).Or(Column("c2").Greater(Param("min")).And(Column("c3").Equal(5)))).SetLimit(10)
+	s1 := `SELECT * FROM "t1" WHERE "c1" = $1 OR ("c2" > $2 AND "c3" = $3) LIMIT 10`
+	s, values := b.Build(q1)
+	if s != s1 {
+		t.Fatalf("Expected %q got %q", s1, s)
+	}
+	v1 := []any{int64(1), 100, 5}
+	if v, err := Bind(values, map[string]any{"id": int64(1), "min": 100}); err != nil {
+		t.Fatal("Error:", err)
+	} else if !reflect.DeepEqual(v, v1) {
+		t.Fatalf("Expected %v got %v", v1, v)
+	}
+	type args struct {
+		ID  int64 `db:"id"`
+		Min int   `db:"min,omitempty"`
+		Max int
+	}
+	v2 := []any{int64(2), 200, 5}
+	if v, err := Bind(values, &args{ID: 2, Min: 200}); err != nil {
+		t.Fatal("Error:", err)
+	} else if !reflect.DeepEqual(v, v2) {
+		t.Fatalf("Expected %v got %v", v2, v)
+	}
+	if _, err := Bind(values, map[string]any{"id": 1}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := Bind(values, struct{}{}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := Bind(values, 123); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestParamNotBound(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("name" TEXT)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := db.ExecContext(
+		ctx, `INSERT INTO "t1" ("name") VALUES ('id')`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	var count int
+	query := db.Select("t1").SetExprs(Raw("COUNT(*)")).
+		SetWhere(Column("name").Equal(Param("id")))
+	err := QueryRow(ctx, db, query).Scan(&count)
+	if err == nil || !strings.Contains(err.Error(), `parameter "id" is not bound`) {
+		t.Fatalf("Expected not bound error, got %v", err)
+	}
+	rawQuery, values := db.Build(query)
+	values, err = Bind(values, map[string]any{"id": "id"})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := db.QueryRowContext(ctx, rawQuery, values...).Scan(&count); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 row, got %d", count)
+	}
+}
+
+func TestPlaceholderReuse(t *testing.T) {
+	b := NewBuilder(PostgresDialect, WithPlaceholderReuse(true))
+	where := Column("c1").Equal(Param("id")).
+		Or(Column("c2").Equal(Param("id"))).
+		Or(Column("c3").Equal(5)).
+		Or(Column("c4").Equal(int64(5))).
+		Or(Column("c5").Equal(5)).
+		Or(Column("c6").Equal([]byte("5"))).
+		Or(Column("c7").Equal([]byte("5")))
+	q1 := b.Select("t1").SetWhere(where)
+	s1 := `SELECT * FROM "t1" WHERE "c1" = $1 OR "c2" = $1 OR "c3" = $2 OR "c4" = $3 OR "c5" = $2 OR "c6" = $4 OR "c7" = $5`
+	v1 := []any{Param("id"), 5, int64(5), []byte("5"), []byte("5")}
+	if s, v := b.Build(q1); s != s1 || !reflect.DeepEqual(v, v1) {
+		t.Fatalf("Expected %q %v got %q %v", s1, v1, s, v)
+	}
+	// Placeholder is reused for columns of different types, so Postgres
+	// deduces inconsistent types for such query.
+	q3 := b.Select("t1").SetWhere(
+		Column("int_col").Equal("1").Or(Column("text_col").Equal("1")).
+			Or(Column("int_col").Equal(Param("p")).Or(Column("text_col").Equal(Param("p")))),
+	)
+	s3 := `SELECT * FROM "t1" WHERE "int_col" = $1 OR "text_col" = $1 OR "int_col" = $2 OR "text_col" = $2`
+	v3 := []any{"1", Param("p")}
+	if s, v := b.Build(q3); s != s3 || !reflect.DeepEqual(v, v3) {
+		t.Fatalf("Expected %q %v got %q %v", s3, v3, s, v)
+	}
+	if s := NewBuilder(PostgresDialect).BuildString(q3); s != `SELECT * FROM "t1" WHERE "int_col" = $1 OR "text_col" = $2 OR "int_col" = $3 OR "text_col" = $4` {
+		t.Fatalf("Unexpected query %q", s)
+	}
+	sqlite := NewBuilder(SQLiteDialect, WithPlaceholderReuse(true))
+	q2 := sqlite.Select("t1").SetWhere(Column("c1").Equal(Param("id")).Or(Column("c2").Equal(Param("id"))))
+	s2 := `SELECT * FROM "t1" WHERE "c1" = $1 OR "c2" = $2`
+	if s := sqlite.BuildString(q2); s != s2 {
+		t.Fatalf("Expected %q got %q", s2, s)
+	}
+}
+
+func TestBuildInline(t *testing.T) {
+	redact := func(column string, value any) any {
+		if column == "password" {
+			return "***"
+		}
+		return value
+	}
+	sqlite := NewBuilder(SQLiteDialect, WithRedactor(redact))
+	postgres := NewBuilder(PostgresDialect, WithRedactor(redact))
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var nilPtr *int
+	inputs := []Query{
+		sqlite.Select("t1").SetWhere(Column("c1").Equal("it's").And(Column("c2").Equal(true)).And(Column("c3").Less(1.5))),
+		sqlite.Insert("t1").SetNames("login", "password", "data", "time").SetValues("user", "secret", []byte{0xde, 0xad}, now),
+		sqlite.Update("t1").SetNames("password", "c2").SetValues("secret", nilPtr),
+		sqlite.Select("t1").SetWhere(Column("password").Equal("secret").Or(Column("c1").Equal(Param("id")))),
+		sqlite.Insert("t1").SetNames("c1", "c2").SetValues(sql.NullString{}, sql.NullInt64{Int64: 5, Valid: true}),
+	}
+	sqliteOutputs := []string{
+		`/* not for execution */ SELECT * FROM "t1" WHERE "c1" = 'it''s' AND "c2" = 1 AND "c3" < 1.5`,
+		`/* not for execution */ INSERT INTO "t1" ("login", "password", "data", "time") VALUES ('user', '***', X'dead', '2020-01-02 03:04:05Z')`,
+		`/* not for execution */ UPDATE "t1" SET "password" = '***', "c2" = NULL WHERE 1 = 1`,
+		`/* not for execution */ SELECT * FROM "t1" WHERE "password" = '***' OR "c1" = :id`,
+		`/* not for execution */ INSERT INTO "t1" ("c1", "c2") VALUES (NULL, 5)`,
+	}
+	for i, input := range inputs {
+		if query := BuildInline(sqlite, input); query != sqliteOutputs[i] {
+			t.Errorf("Expected %q, got %q", sqliteOutputs[i], query)
+		}
+	}
+	q1 := postgres.Insert("t1").SetNames("c1", "c2", "c3").SetValues(false, []byte{0xbe, 0xef}, uint8(7))
+	s1 := `/* not for execution */ INSERT INTO "t1" ("c1", "c2", "c3") VALUES (FALSE, '\xbeef'::bytea, 7)`
+	if query := BuildInline(postgres, q1); query != s1 {
+		t.Errorf("Expected %q, got %q", s1, query)
+	}
+	q2 := sqlite.Select("t1").SetWhere(Column("t1.password").Equal("secret").And(Lower(Column("password")).Equal("secret")))
+	s2 := `/* not for execution */ SELECT * FROM "t1" WHERE "t1"."password" = 'secret' AND LOWER("password") = 'secret'`
+	if query := BuildInline(sqlite, q2); query != s2 {
+		t.Errorf("Expected %q, got %q", s2, query)
+	}
+	custom := struct{ Builder }{Builder: sqlite}
+	s3 := `/* not for execution */ SELECT * FROM "t1" WHERE "password" = 'secret' OR "c1" = :id`
+	if query := BuildInline(custom, inputs[3]); query != s3 {
+		t.Errorf("Expected %q, got %q", s3, query)
+	}
+}
+
+func TestIn(t *testing.T) {
+	b := NewBuilder(SQLiteDialect)
+	inputs := []BoolExpr{
+		Column("c1").In(1, 2, 3),
+		Column("c1").NotIn("a"),
+		Column("c1").In(),
+		Column("c1").NotIn(),
+		Lower(Column("c1")).In(Column("c2"), "b").And(Column("c3").Equal(4)),
+	}
+	outputs := []string{
+		`SELECT * FROM "t1" WHERE "c1" IN ($1, $2, $3)`,
+		`SELECT * FROM "t1" WHERE "c1" NOT IN ($1)`,
+		`SELECT * FROM "t1" WHERE 1 = 0`,
+		`SELECT * FROM "t1" WHERE 1 = 1`,
+		`SELECT * FROM "t1" WHERE LOWER("c1") IN ("c2", $1) AND "c3" = $2`,
+	}
+	for i, input := range inputs {
+		if query := b.BuildString(b.Select("t1").SetWhere(input)); query != outputs[i] {
+			t.Errorf("Expected %q, got %q", outputs[i], query)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	sqlite := NewBuilder(SQLiteDialect)
+	postgres := NewBuilder(PostgresDialect)
+	q1 := sqlite.Select("t1").SetWhere(Column("id").In(1, 2, 3).And(Column("c1").Equal("a"))).SetLimit(10)
+	q2 := postgres.Select("t1").SetWhere(Column("id").In(4, 5).And(Column("c1").Equal(Param("c1")))).SetLimit(20)
+	s1 := `SELECT * FROM "t1" WHERE "id" IN (...) AND "c1" = ? LIMIT ?`
+	if s := Fingerprint(q1); s != s1 {
+		t.Fatalf("Expected %q got %q", s1, s)
+	}
+	if s := Fingerprint(q2); s != s1 {
+		t.Fatalf("Expected %q got %q", s1, s)
+	}
+	if FingerprintHash(q1) != FingerprintHash(q2) {
+		t.Fatal("Hashes should be equal")
+	}
+	q3 := postgres.Insert("t1").SetNames("c1", "c2").SetValues(1, Now())
+	s3 := `INSERT INTO "t1" ("c1", "c2") VALUES (?, NOW())`
+	if s := Fingerprint(q3); s != s3 {
+		t.Fatalf("Expected %q got %q", s3, s)
+	}
+	if FingerprintHash(q1) == FingerprintHash(q3) {
+		t.Fatal("Hashes should differ")
+	}
+}
+
+func TestPrettyPrint(t *testing.T) {
+	b := NewBuilder(PostgresDialect, WithPrettyPrint(true))
+	where := Column("c1").Greater(0).And(Column("c1").LessEqual(100).Or(Column("c1").Less(-10))).And(Column("c2").Equal(1))
+	q1 := b.Select("t1").SetNames("c1", "c2").SetWhere(where).SetOrderBy("c1").SetLimit(5)
+	s1 := `SELECT "c1", "c2"
+FROM "t1"
+WHERE "c1" > $1
+  AND ("c1" <= $2
+    OR "c1" < $3)
+  AND "c2" = $4
+ORDER BY "c1" ASC
+LIMIT 5`
+	if s := b.BuildString(q1); s != s1 {
+		t.Fatalf("Expected %q got %q", s1, s)
+	}
+	q2 := b.Insert("t1").SetNames("c1", "c2").SetValues(1, 2).
+		(*PostgresInsertQuery).SetReturning("id")
+	s2 := `INSERT INTO "t1" ("c1", "c2")
+VALUES ($1, $2)
+RETURNING "id"`
+	if s := b.BuildString(q2); s != s2 {
+		t.Fatalf("Expected %q got %q", s2, s)
+	}
+	q3 := b.Update("t1").SetNames("c1").SetValues(1)
+	s3 := `UPDATE "t1"
+SET "c1" = $1
+WHERE 1 = 1`
+	if s := b.BuildString(q3); s != s3 {
+		t.Fatalf("Expected %q got %q", s3, s)
+	}
+	q4 := b.Delete("t1").SetWhere(Column("c1").Equal(1))
+	s4 := `DELETE FROM "t1"
+WHERE "c1" = $1`
+	if s := b.BuildString(q4); s != s4 {
+		t.Fatalf("Expected %q got %q", s4, s)
+	}
+}
+
+func TestClone(t *testing.T) {
+	b := NewBuilder(PostgresDialect)
+	baseSelect := b.Select("t1").SetNames("c1", "c2").SetOrderBy("c1")
+	baseUpdate := b.Update("t1").SetNames("c1").SetValues(1)
+	baseDelete := b.Delete("t1")
+	baseInsert := b.Insert("t1").SetNames("c1").SetValues(1).(*PostgresInsertQuery).SetReturning("id")
+	inputs := []Query{
+		baseSelect.Clone().SetWhere(Column("c1").Equal(1)).SetLimit(5),
+		baseSelect.Clone().SetNames("c3").SetOrderBy(Descending("c2")),
+		baseUpdate.Clone().SetWhere(Column("c2").Equal(2)),
+		baseDelete.Clone().SetWhere(Column("c1").Equal(1)),
+		baseInsert.Clone().SetNames("c1", "c2").SetValues(1, 2).(*PostgresInsertQuery).SetReturning("c3"),
+		baseSelect.SetExprs("c4").SetWhere(Column("c1").Equal(1)).SetOrderBy("c4").SetLimit(1),
+		baseUpdate.SetNames("c2").SetValues(2).SetWhere(Column("c1").Equal(1)),
+		baseDelete.SetWhere(Column("c2").Equal(2)),
+		baseInsert.SetReturning("c2").SetValues(2),
+		baseSelect,
+		baseUpdate,
+		baseDelete,
+		baseInsert,
+	}
+	outputs := []string{
+		`SELECT "c1", "c2" FROM "t1" WHERE "c1" = $1 ORDER BY "c1" ASC LIMIT 5`,
+		`SELECT "c3" FROM "t1" WHERE 1 = 1 ORDER BY "c2" DESC`,
+		`UPDATE "t1" SET "c1" = $1 WHERE "c2" = $2`,
+		`DELETE FROM "t1" WHERE "c1" = $1`,
+		`INSERT INTO "t1" ("c1", "c2") VALUES ($1, $2) RETURNING "c3"`,
+		`SELECT "c4" FROM "t1" WHERE "c1" = $1 ORDER BY "c4" ASC LIMIT 1`,
+		`UPDATE "t1" SET "c2" = $1 WHERE "c1" = $2`,
+		`DELETE FROM "t1" WHERE "c2" = $1`,
+		`INSERT INTO "t1" ("c1") VALUES ($1) RETURNING "c2"`,
+		`SELECT "c1", "c2" FROM "t1" WHERE 1 = 1 ORDER BY "c1" ASC`,
+		`UPDATE "t1" SET "c1" = $1 WHERE 1 = 1`,
+		`DELETE FROM "t1" WHERE 1 = 1`,
+		`INSERT INTO "t1" ("c1") VALUES ($1) RETURNING "id"`,
+	}
+	for i, input := range inputs {
+		if query := b.BuildString(input); query != outputs[i] {
+			t.Errorf("Expected %q, got %q", outputs[i], query)
+		}
+	}
+}
+
+type testRowBase struct {
+	ID int64 `db:"id,autoincrement"`
+}
+
+type testRow struct {
+	testRowBase
+	Name      string  `db:"name"`
+	Email     string  `db:"email,omitempty"`
+	Age       *int    `db:"age,omitempty"`
+	CreatedAt int64   `db:"created_at,readonly"`
+	Score     float64 `db:"score"`
+	Ignored   string
+}
+
+func TestSetRow(t *testing.T) {
+	b := NewBuilder(PostgresDialect)
+	row := testRow{testRowBase: testRowBase{ID: 1}, Name: "test", CreatedAt: 5, Score: 1.5}
+	q1 := b.Insert("t1").SetRow(row).(*PostgresInsertQuery).SetReturning("id")
+	s1 := `INSERT INTO "t1" ("name", "score") VALUES ($1, $2) RETURNING "id"`
+	v1 := []any{"test", 1.5}
+	if s, v := b.Build(q1); s != s1 || !reflect.DeepEqual(v, v1) {
+		t.Fatalf("Expected %q %v got %q %v", s1, v1, s, v)
+	}
+	age := 0
+	row.Email, row.Age = "a@a", &age
+	q2 := b.Update("t1").SetRow(&row).SetWhere(Column("id").Equal(row.ID))
+	s2 := `UPDATE "t1" SET "name" = $1, "email" = $2, "age" = $3, "score" = $4 WHERE "id" = $5`
+	v2 := []any{"test", "a@a", &age, 1.5, int64(1)}
+	if s, v := b.Build(q2); s != s2 || !reflect.DeepEqual(v, v2) {
+		t.Fatalf("Expected %q %v got %q %v", s2, v2, s, v)
+	}
+	row.Email = ""
+	q3 := b.Update("t1").SetRow(row, "email", "score")
+	s3 := `UPDATE "t1" SET "email" = $1, "score" = $2 WHERE 1 = 1`
+	v3 := []any{"", 1.5}
+	if s, v := b.Build(q3); s != s3 || !reflect.DeepEqual(v, v3) {
+		t.Fatalf("Expected %q %v got %q %v", s3, v3, s, v)
+	}
+	testExpectPanic(t, func() {
+		b.Update("t1").SetRow(row, "id")
+	})
+	testExpectPanic(t, func() {
+		b.Insert("t1").SetRow(row, "unknown")
+	})
+	testExpectPanic(t, func() {
+		b.Insert("t1").SetRow(123)
+	})
+}
+
 func testExpectPanic(tb testing.TB, fn func()) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -184,3 +669,44 @@ func testExpectPanic(tb testing.TB, fn func()) {
 	}()
 	fn()
 }
+
+type testCustomWriter struct {
+	query  strings.Builder
+	values []any
+}
+
+func (w *testCustomWriter) WriteRune(r rune)     { w.query.WriteRune(r) }
+func (w *testCustomWriter) WriteString(s string) { w.query.WriteString(s) }
+func (w *testCustomWriter) WriteName(n string)   { w.query.WriteString(n) }
+func (w *testCustomWriter) WriteValue(v any)     { w.values = append(w.values, v); w.query.WriteRune('?') }
+func (w *testCustomWriter) String() string       { return w.query.String() }
+func (w *testCustomWriter) Values() []any        { return w.values }
+
+func TestCustomWriter(t *testing.T) {
+	query := func(b Builder) Query {
+		return b.Select("t1").SetExprs(Now(), Cast(Column("c1"), TextType)).
+			SetWhere(Column("c2").Equal(1))
+	}
+	w1 := &testCustomWriter{}
+	query(NewBuilder(SQLiteDialect)).WriteQuery(w1)
+	s1 := `SELECT CURRENT_TIMESTAMP, CAST(c1 AS TEXT) FROM t1 WHERE c2 = ?`
+	if v := w1.String(); v != s1 {
+		t.Fatalf("Expected %q got %q", s1, v)
+	}
+	w2 := &testCustomWriter{}
+	query(NewBuilder(PostgresDialect)).WriteQuery(w2)
+	s2 := `SELECT NOW(), CAST(c1 AS TEXT) FROM t1 WHERE c2 = ?`
+	if v := w2.String(); v != s2 {
+		t.Fatalf("Expected %q got %q", s2, v)
+	}
+	w3 := &testCustomWriter{}
+	NewBuilder(PostgresDialect).Insert("t1").SetNames("c1").SetValues(1).WriteQuery(w3)
+	s3 := `INSERT INTO t1 (c1) VALUES (?)`
+	if v := w3.String(); v != s3 {
+		t.Fatalf("Expected %q got %q", s3, v)
+	}
+	testExpectPanic(t, func() {
+		NewBuilder(PostgresDialect).Insert("t1").SetNames("c1").SetValues(1).
+			WriteQuery(dialectWriter{Writer: &testCustomWriter{}, dialect: SQLiteDialect})
+	})
+}
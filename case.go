@@ -0,0 +1,116 @@
+package gosql
+
+// CaseExpr represents conditional expression:
+// "CASE WHEN cond THEN result ... ELSE result END".
+type CaseExpr interface {
+	Value
+	// When returns expression with additional branch.
+	When(cond any, result any) CaseExpr
+	// Else returns expression with specified default result.
+	Else(result any) CaseExpr
+}
+
+type caseWhen struct {
+	cond   Expr
+	result Value
+}
+
+type caseExpr struct {
+	operand Value
+	whens   []caseWhen
+	orElse  Value
+}
+
+// Case creates a new conditional expression.
+//
+// Without operand it represents searched form where each condition of
+// When is boolean expression: "CASE WHEN cond THEN result END".
+// With operand it represents simple form where each condition of When
+// is compared with operand: "CASE operand WHEN value THEN result END".
+func Case(operand ...any) CaseExpr {
+	switch len(operand) {
+	case 0:
+		return caseExpr{}
+	case 1:
+		return caseExpr{operand: wrapValue(operand[0])}
+	default:
+		panic("case expression can have at most one operand")
+	}
+}
+
+// When returns expression with additional branch.
+func (e caseExpr) When(cond any, result any) CaseExpr {
+	// Copy branches to keep original expression unchanged.
+	e.whens = append(e.whens[:len(e.whens):len(e.whens)], caseWhen{
+		cond:   wrapCaseCond(cond),
+		result: wrapValue(result),
+	})
+	return e
+}
+
+// Else returns expression with specified default result.
+func (e caseExpr) Else(result any) CaseExpr {
+	e.orElse = wrapValue(result)
+	return e
+}
+
+func (e caseExpr) Equal(o any) BoolExpr {
+	return cmp{kind: eqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e caseExpr) NotEqual(o any) BoolExpr {
+	return cmp{kind: notEqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e caseExpr) Less(o any) BoolExpr {
+	return cmp{kind: lessCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e caseExpr) Greater(o any) BoolExpr {
+	return cmp{kind: greaterCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e caseExpr) LessEqual(o any) BoolExpr {
+	return cmp{kind: lessEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e caseExpr) GreaterEqual(o any) BoolExpr {
+	return cmp{kind: greaterEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e caseExpr) In(o ...any) BoolExpr {
+	return newIn(e, false, o)
+}
+
+func (e caseExpr) NotIn(o ...any) BoolExpr {
+	return newIn(e, true, o)
+}
+
+func (e caseExpr) WriteExpr(w Writer) {
+	if len(e.whens) == 0 {
+		panic("case expression requires at least one branch")
+	}
+	w.WriteString("CASE")
+	if e.operand != nil {
+		w.WriteRune(' ')
+		e.operand.WriteExpr(w)
+	}
+	for _, when := range e.whens {
+		w.WriteString(" WHEN ")
+		when.cond.WriteExpr(w)
+		w.WriteString(" THEN ")
+		when.result.WriteExpr(w)
+	}
+	if e.orElse != nil {
+		w.WriteString(" ELSE ")
+		e.orElse.WriteExpr(w)
+	}
+	w.WriteString(" END")
+}
+
+func wrapCaseCond(cond any) Expr {
+	if e, ok := cond.(Expr); ok {
+		return e
+	}
+	return value{value: cond}
+}
@@ -0,0 +1,170 @@
+package gosql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type dataTypeKind int
+
+const (
+	integerType dataTypeKind = iota
+	bigIntType
+	textType
+	booleanType
+	timestampType
+	jsonType
+	uuidType
+	numericType
+)
+
+// DataType represents portable SQL data type.
+type DataType struct {
+	kind      dataTypeKind
+	precision int
+	scale     int
+}
+
+var (
+	// IntegerType represents integer type.
+	IntegerType = DataType{kind: integerType}
+	// BigIntType represents 64-bit integer type.
+	BigIntType = DataType{kind: bigIntType}
+	// TextType represents text type.
+	TextType = DataType{kind: textType}
+	// BooleanType represents boolean type.
+	BooleanType = DataType{kind: booleanType}
+	// TimestampType represents timestamp type.
+	TimestampType = DataType{kind: timestampType}
+	// JSONType represents JSON type.
+	JSONType = DataType{kind: jsonType}
+	// UUIDType represents UUID type.
+	UUIDType = DataType{kind: uuidType}
+)
+
+// NumericType represents exact numeric type with specified precision
+// and scale.
+func NumericType(precision, scale int) DataType {
+	return DataType{kind: numericType, precision: precision, scale: scale}
+}
+
+func (t DataType) writeType(w Writer) {
+	switch d, _ := writerDialect(w); d {
+	case SQLiteDialect:
+		t.writeSQLiteType(w)
+	default:
+		t.writePostgresType(w)
+	}
+}
+
+// writeSQLiteType writes type name with matching SQLite type affinity.
+func (t DataType) writeSQLiteType(w Writer) {
+	switch t.kind {
+	case integerType, bigIntType, booleanType:
+		w.WriteString("INTEGER")
+	case textType, timestampType, jsonType, uuidType:
+		w.WriteString("TEXT")
+	case numericType:
+		w.WriteString("NUMERIC")
+	default:
+		panic(fmt.Errorf("unsupported data type: %d", t.kind))
+	}
+}
+
+func (t DataType) writePostgresType(w Writer) {
+	switch t.kind {
+	case integerType:
+		w.WriteString("INTEGER")
+	case bigIntType:
+		w.WriteString("BIGINT")
+	case textType:
+		w.WriteString("TEXT")
+	case booleanType:
+		w.WriteString("BOOLEAN")
+	case timestampType:
+		w.WriteString("TIMESTAMP")
+	case jsonType:
+		w.WriteString("JSON")
+	case uuidType:
+		w.WriteString("UUID")
+	case numericType:
+		w.WriteString("NUMERIC(")
+		w.WriteString(strconv.Itoa(t.precision))
+		w.WriteString(", ")
+		w.WriteString(strconv.Itoa(t.scale))
+		w.WriteRune(')')
+	default:
+		panic(fmt.Errorf("unsupported data type: %d", t.kind))
+	}
+}
+
+type castExpr struct {
+	expr     Value
+	dataType DataType
+}
+
+// Cast build expression: "CAST(expr AS type)".
+//
+// For Postgres builder created with WithCastShorthand option it is
+// rendered as "expr::type".
+func Cast(expr any, dataType DataType) Value {
+	return castExpr{expr: wrapValue(expr), dataType: dataType}
+}
+
+func (e castExpr) Equal(o any) BoolExpr {
+	return cmp{kind: eqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e castExpr) NotEqual(o any) BoolExpr {
+	return cmp{kind: notEqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e castExpr) Less(o any) BoolExpr {
+	return cmp{kind: lessCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e castExpr) Greater(o any) BoolExpr {
+	return cmp{kind: greaterCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e castExpr) LessEqual(o any) BoolExpr {
+	return cmp{kind: lessEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e castExpr) GreaterEqual(o any) BoolExpr {
+	return cmp{kind: greaterEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e castExpr) In(o ...any) BoolExpr {
+	return newIn(e, false, o)
+}
+
+func (e castExpr) NotIn(o ...any) BoolExpr {
+	return newIn(e, true, o)
+}
+
+func (e castExpr) WriteExpr(w Writer) {
+	if isCastShorthand(w) {
+		switch e.expr.(type) {
+		case Column, value, funcExpr, castExpr:
+			e.expr.WriteExpr(w)
+		default:
+			w.WriteRune('(')
+			e.expr.WriteExpr(w)
+			w.WriteRune(')')
+		}
+		w.WriteString("::")
+		e.dataType.writeType(w)
+		return
+	}
+	w.WriteString("CAST(")
+	e.expr.WriteExpr(w)
+	w.WriteString(" AS ")
+	e.dataType.writeType(w)
+	w.WriteRune(')')
+}
+
+func isCastShorthand(w Writer) bool {
+	wr, ok := w.(*writer)
+	return ok && wr.builder.castShorthand && wr.Dialect() == PostgresDialect
+}
@@ -0,0 +1,58 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Cursor represents iterator that lazily scans rows into structs.
+//
+// Cursor should be closed after use.
+type Cursor[T any] struct {
+	rows    *sql.Rows
+	scanner *rowScanner[T]
+}
+
+// NewCursor builds and executes select query and returns cursor over
+// its rows.
+//
+// Columns are mapped to struct fields like in SelectAll.
+func NewCursor[T any](
+	ctx context.Context, r QueryRunner, query SelectQuery, options ...ScanOption,
+) (*Cursor[T], error) {
+	query, err := selectStructNames[T](query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := QueryRows(ctx, r, query)
+	if err != nil {
+		return nil, err
+	}
+	scanner, err := newRowScanner[T](rows, options)
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	return &Cursor[T]{rows: rows, scanner: scanner}, nil
+}
+
+// Next prepares next row for Scan and returns false when there are no
+// more rows or error occurred.
+func (c *Cursor[T]) Next() bool {
+	return c.rows.Next()
+}
+
+// Scan returns current row.
+func (c *Cursor[T]) Scan() (T, error) {
+	return c.scanner.scan(c.rows)
+}
+
+// Err returns error that was encountered during iteration.
+func (c *Cursor[T]) Err() error {
+	return c.rows.Err()
+}
+
+// Close closes cursor.
+func (c *Cursor[T]) Close() error {
+	return c.rows.Close()
+}
@@ -0,0 +1,38 @@
+package gosql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	testPrepareScanTable(t, db)
+	ctx := context.Background()
+	cursor, err := NewCursor[testScanRow](ctx, db, db.Select("t1").SetOrderBy("id"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var ids []int64
+	for cursor.Next() {
+		row, err := cursor.Scan()
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		ids = append(ids, row.ID)
+	}
+	if err := cursor.Err(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := cursor.Close(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("Unexpected ids: %v", ids)
+	}
+	if _, err := NewCursor[testScanRow](
+		ctx, db, db.Select("t1").SetNames("id"), WithStrictColumns(true),
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+}
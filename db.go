@@ -3,6 +3,12 @@ package gosql
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 )
 
 // Runner represents SQL interface like sql.DB or sql.Tx.
@@ -58,33 +64,469 @@ func WithIsolation(level sql.IsolationLevel) BeginTxOption {
 	}
 }
 
+// WithPanicRecovery represents option for WrapTx that recovers panics.
+//
+// When fn panics, transaction or savepoint is rolled back and PanicError
+// is returned instead of panic. When fn returns error, errors of rollback
+// are joined with returned error using errors.Join.
+func WithPanicRecovery() BeginTxOption {
+	return withTxOptions(func(opts *txOptions) {
+		opts.recoverPanic = true
+	})
+}
+
+// txOptions represents options of transaction wrappers.
+type txOptions struct {
+	tx           *sql.TxOptions
+	retry        *RetryPolicy
+	recoverPanic bool
+}
+
+// activeTxOptions contains options of wrappers that are applying
+// options at the moment, keyed by address of txOptions.tx.
+var activeTxOptions sync.Map
+
+// withTxOptions returns option that modifies options of transaction
+// wrappers and is ignored when applied to plain *sql.TxOptions.
+func withTxOptions(fn func(opts *txOptions)) BeginTxOption {
+	return func(txOpts **sql.TxOptions) {
+		if opts, ok := activeTxOptions.Load(txOpts); ok {
+			fn(opts.(*txOptions))
+		}
+	}
+}
+
+func newTxOptions(options []BeginTxOption) txOptions {
+	var opts txOptions
+	activeTxOptions.Store(&opts.tx, &opts)
+	defer activeTxOptions.Delete(&opts.tx)
+	for _, option := range options {
+		option(&opts.tx)
+	}
+	return opts
+}
+
+// PanicError represents panic recovered inside transaction.
+type PanicError struct {
+	// Value contains value passed to panic.
+	Value any
+	// Stack contains stack trace of goroutine at the moment of panic.
+	Stack []byte
+	// RollbackErr contains error of rollback if any.
+	RollbackErr error
+}
+
+// Error returns string representation of panic.
+func (e *PanicError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf(
+			"panic in transaction: %v (rollback: %v)", e.Value, e.RollbackErr,
+		)
+	}
+	return fmt.Sprintf("panic in transaction: %v", e.Value)
+}
+
+// Unwrap returns panic value if it is error and rollback error.
+func (e *PanicError) Unwrap() []error {
+	var errs []error
+	if err, ok := e.Value.(error); ok {
+		errs = append(errs, err)
+	}
+	if e.RollbackErr != nil {
+		errs = append(errs, e.RollbackErr)
+	}
+	return errs
+}
+
+// CommitError represents error of transaction commit.
+//
+// When commit fails, state of transaction can be unknown, so such errors
+// should be handled separately from errors of wrapped code.
+type CommitError struct {
+	Err error
+}
+
+// Error returns string representation of commit error.
+func (e *CommitError) Error() string {
+	return "cannot commit transaction: " + e.Err.Error()
+}
+
+// Unwrap returns original error.
+func (e *CommitError) Unwrap() error {
+	return e.Err
+}
+
 // WrapTx represents wrapper for code that should use transaction.
+//
+// Errors of commit are wrapped into CommitError. Callbacks for transaction
+// can be registered using OnCommit and OnRollback of Tx, see DB.WithTx.
 func WrapTx(
 	ctx context.Context,
 	b TxBeginner,
 	fn func(tx *sql.Tx) error,
 	options ...BeginTxOption,
 ) error {
-	var opts *sql.TxOptions
-	for _, option := range options {
-		option(&opts)
+	opts := newTxOptions(options)
+	return opts.run(ctx, func() error {
+		return wrapTx(ctx, b, fn, opts)
+	})
+}
+
+func (o txOptions) run(ctx context.Context, fn func() error) error {
+	if o.retry != nil {
+		return o.retry.run(ctx, fn)
 	}
-	tx, err := b.BeginTx(ctx, opts)
+	return fn()
+}
+
+func wrapTx(
+	ctx context.Context,
+	b TxBeginner,
+	fn func(tx *sql.Tx) error,
+	opts txOptions,
+) error {
+	tx, err := b.BeginTx(ctx, opts.tx)
 	if err != nil {
 		return err
 	}
+	hooks := &txHooks{}
+	activeTxHooks.Store(tx, hooks)
+	defer activeTxHooks.Delete(tx)
+	return hooks.wrap(nil, func() error {
+		return runTx(tx, fn, opts)
+	})
+}
+
+func runTx(tx *sql.Tx, fn func(tx *sql.Tx) error, opts txOptions) (err error) {
 	rollback := true
 	defer func() {
-		if rollback {
+		if !rollback {
+			return
+		}
+		if !opts.recoverPanic {
 			// Try to rollback transaction on error or panic.
 			_ = tx.Rollback()
+			return
 		}
+		recovered := recover()
+		rollbackErr := tx.Rollback()
+		if errors.Is(rollbackErr, sql.ErrTxDone) {
+			rollbackErr = nil
+		}
+		err = rollbackError(err, recovered, rollbackErr)
 	}()
 	if err := fn(tx); err != nil {
 		return err
 	}
 	rollback = false
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return &CommitError{Err: err}
+	}
+	return nil
+}
+
+// rollbackError returns error of rolled back transaction or savepoint.
+//
+// It should be called from deferred function, so stack trace of panic
+// is still available.
+func rollbackError(err error, recovered any, rollbackErr error) error {
+	if recovered != nil {
+		return &PanicError{
+			Value:       recovered,
+			Stack:       debug.Stack(),
+			RollbackErr: rollbackErr,
+		}
+	}
+	if rollbackErr != nil {
+		return errors.Join(err, rollbackErr)
+	}
+	return err
+}
+
+// savepointID is used for generating unique savepoint names.
+var savepointID uint64
+
+// WrapNestedTx represents wrapper for code that should use transaction
+// and can be called inside another transaction.
+//
+// When runner is transaction (*sql.Tx or *Tx), fn is called inside
+// savepoint of this transaction: savepoint is released on success and
+// rolled back on error or panic. Only WithPanicRecovery option can be
+// used in this case and ErrNestedTxOptions is returned for other options.
+// Otherwise runner should implement TxBeginner and WrapTx is used.
+func WrapNestedTx(
+	ctx context.Context,
+	r Runner,
+	fn func(tx *sql.Tx) error,
+	options ...BeginTxOption,
+) error {
+	switch v := r.(type) {
+	case *sql.Tx:
+		return wrapSavepoint(ctx, v, fn, newTxOptions(options))
+	case *Tx:
+		return wrapSavepoint(ctx, v.Tx, fn, newTxOptions(options))
+	case TxBeginner:
+		return WrapTx(ctx, v, fn, options...)
+	default:
+		return fmt.Errorf("runner %T does not support transactions", r)
+	}
+}
+
+// ErrNestedTxOptions is returned when transaction options or retry
+// policy are passed to wrapper that uses savepoint of existing
+// transaction.
+var ErrNestedTxOptions = errors.New("transaction options are not supported for savepoints")
+
+// txKey represents key of context value with transaction started by
+// specified beginner.
+type txKey struct {
+	b TxBeginner
+}
+
+// newTxKey returns context key for transactions of beginner.
+//
+// Beginner should be comparable, otherwise transactions can not be
+// stored in context.
+func newTxKey(b TxBeginner) (txKey, bool) {
+	if b == nil || !reflect.TypeOf(b).Comparable() {
+		return txKey{}, false
+	}
+	return txKey{b: b}, true
+}
+
+// TxFromContext returns transaction started by WrapTxContext with
+// specified beginner.
+func TxFromContext(ctx context.Context, b TxBeginner) (*Tx, bool) {
+	key, ok := newTxKey(b)
+	if !ok {
+		return nil, false
+	}
+	tx, ok := ctx.Value(key).(*Tx)
+	return tx, ok
+}
+
+// RunnerFromContext returns transaction from context if present or
+// specified runner otherwise.
+//
+// Only transaction started by WrapTxContext with the same runner is
+// returned. When runner provides builder like DB does, transaction is
+// wrapped into Tx with this builder.
+func RunnerFromContext(ctx context.Context, r Runner) Runner {
+	b, ok := r.(TxBeginner)
+	if !ok {
+		return r
+	}
+	tx, ok := TxFromContext(ctx, b)
+	if !ok {
+		return r
+	}
+	if builder, ok := r.(Builder); ok {
+		return &Tx{Tx: tx.Tx, Builder: builder}
+	}
+	return tx.Tx
+}
+
+// RunnerFromContext returns transaction of database from context bound
+// to builder of database if present or database itself otherwise.
+func (d *DB) RunnerFromContext(ctx context.Context) QueryRunner {
+	if tx, ok := TxFromContext(ctx, d); ok {
+		return &Tx{Tx: tx.Tx, Builder: d.Builder}
+	}
+	return d
+}
+
+// WrapTxContext represents wrapper for code that should use transaction
+// passed through context.
+//
+// Transaction is stored in context passed to fn, so TxFromContext and
+// RunnerFromContext with the same beginner can be used to join it.
+// When context already contains transaction of this beginner, fn is
+// called inside savepoint of this transaction like in WrapNestedTx.
+// In this case only WithPanicRecovery option can be used and
+// ErrNestedTxOptions is returned for other options.
+//
+// Callbacks registered with OnCommit are called after transaction is
+// committed and callbacks registered with OnRollback are called after
+// transaction or savepoint is rolled back, including failed commit.
+func WrapTxContext(
+	ctx context.Context,
+	b TxBeginner,
+	fn func(ctx context.Context) error,
+	options ...BeginTxOption,
+) error {
+	key, ok := newTxKey(b)
+	if !ok {
+		return fmt.Errorf("beginner %T is not comparable", b)
+	}
+	builder, _ := b.(Builder)
+	opts := newTxOptions(options)
+	if tx, ok := ctx.Value(key).(*Tx); ok {
+		return wrapSavepoint(ctx, tx.Tx, func(*sql.Tx) error {
+			return fn(ctx)
+		}, opts)
+	}
+	return opts.run(ctx, func() error {
+		return wrapTx(ctx, b, func(tx *sql.Tx) error {
+			return fn(context.WithValue(ctx, key, &Tx{Tx: tx, Builder: builder}))
+		}, opts)
+	})
+}
+
+// ErrNoTxHooks is returned when callbacks are registered for transaction
+// that is not started by WrapTx, WrapTxContext or WrapNestedTx.
+var ErrNoTxHooks = errors.New("transaction does not support callbacks")
+
+// activeTxHooks contains callbacks of innermost savepoint or transaction
+// for transactions started by wrappers.
+var activeTxHooks sync.Map
+
+// OnCommit registers callback that is called after transaction is
+// committed.
+//
+// Transaction should be started by WrapTx, WrapTxContext or WrapNestedTx,
+// otherwise ErrNoTxHooks is returned. Callbacks registered inside
+// savepoint are called after commit of outermost transaction.
+func (t *Tx) OnCommit(fn func()) error {
+	hooks, err := t.getHooks()
+	if err != nil {
+		return err
+	}
+	hooks.add(&hooks.commit, fn)
+	return nil
+}
+
+// OnRollback registers callback that is called after transaction is
+// rolled back.
+//
+// Transaction should be started by WrapTx, WrapTxContext or WrapNestedTx,
+// otherwise ErrNoTxHooks is returned. Callbacks registered inside
+// savepoint are also called when savepoint is rolled back.
+func (t *Tx) OnRollback(fn func()) error {
+	hooks, err := t.getHooks()
+	if err != nil {
+		return err
+	}
+	hooks.add(&hooks.rollback, fn)
+	return nil
+}
+
+func (t *Tx) getHooks() (*txHooks, error) {
+	if t.Tx == nil {
+		return nil, ErrNoTxHooks
+	}
+	hooks, ok := activeTxHooks.Load(t.Tx)
+	if !ok {
+		return nil, ErrNoTxHooks
+	}
+	return hooks.(*txHooks), nil
+}
+
+// txHooks represents callbacks of transaction or savepoint.
+type txHooks struct {
+	mutex    sync.Mutex
+	commit   []func()
+	rollback []func()
+}
+
+func (h *txHooks) add(hooks *[]func(), fn func()) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	*hooks = append(*hooks, fn)
+}
+
+// wrap calls fn and then runs callbacks depending on result.
+//
+// When parent is specified, callbacks of successful fn are moved to
+// parent instead of being called, so they are called only after commit
+// of outermost transaction.
+func (h *txHooks) wrap(parent *txHooks, fn func() error) error {
+	success := false
+	defer func() {
+		h.mutex.Lock()
+		commit, rollback := h.commit, h.rollback
+		h.commit, h.rollback = nil, nil
+		h.mutex.Unlock()
+		switch {
+		case !success:
+			runHooks(rollback)
+		case parent != nil:
+			parent.mutex.Lock()
+			parent.commit = append(parent.commit, commit...)
+			parent.rollback = append(parent.rollback, rollback...)
+			parent.mutex.Unlock()
+		default:
+			runHooks(commit)
+		}
+	}()
+	if err := fn(); err != nil {
+		return err
+	}
+	success = true
+	return nil
+}
+
+func runHooks(hooks []func()) {
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+func wrapSavepoint(
+	ctx context.Context,
+	tx *sql.Tx,
+	fn func(tx *sql.Tx) error,
+	opts txOptions,
+) error {
+	if opts.tx != nil || opts.retry != nil {
+		return ErrNestedTxOptions
+	}
+	parent, ok := activeTxHooks.Load(tx)
+	if !ok {
+		return runSavepoint(ctx, tx, fn, opts)
+	}
+	hooks := &txHooks{}
+	activeTxHooks.Store(tx, hooks)
+	defer activeTxHooks.Store(tx, parent)
+	return hooks.wrap(parent.(*txHooks), func() error {
+		return runSavepoint(ctx, tx, fn, opts)
+	})
+}
+
+func runSavepoint(
+	ctx context.Context,
+	tx *sql.Tx,
+	fn func(tx *sql.Tx) error,
+	opts txOptions,
+) (err error) {
+	name := fmt.Sprintf("gosql_savepoint_%d", atomic.AddUint64(&savepointID, 1))
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+	rollback := true
+	defer func() {
+		if !rollback {
+			return
+		}
+		if !opts.recoverPanic {
+			// Try to rollback savepoint on error or panic.
+			_ = rollbackSavepoint(ctx, tx, name)
+			return
+		}
+		recovered := recover()
+		err = rollbackError(err, recovered, rollbackSavepoint(ctx, tx, name))
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	rollback = false
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+func rollbackSavepoint(ctx context.Context, tx *sql.Tx, name string) error {
+	_, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+	_, releaseErr := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return errors.Join(rollbackErr, releaseErr)
 }
 
 // DB represents wrapper for sql.DB with additional builder and
@@ -1,7 +1,11 @@
 package gosql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -85,4 +89,479 @@ func TestTxOptions(t *testing.T) {
 			t.Fatal("Opts should marked writable")
 		}
 	}
+	{
+		var opts *sql.TxOptions
+		WithRetry(RetryPolicy{})(&opts)
+		WithPanicRecovery()(&opts)
+		if opts != nil {
+			t.Fatal("Opts should not be initialized")
+		}
+	}
+	{
+		opts := newTxOptions([]BeginTxOption{
+			WithReadOnly(true), WithRetry(RetryPolicy{}), WithPanicRecovery(),
+		})
+		if opts.tx == nil || !opts.tx.ReadOnly {
+			t.Fatal("Opts should marked readonly")
+		}
+		if opts.retry == nil {
+			t.Fatal("Opts should contain retry policy")
+		}
+		if !opts.recoverPanic {
+			t.Fatal("Opts should recover panic")
+		}
+	}
+}
+
+func TestWrapNestedTx(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	insert := func(id int) func(tx *sql.Tx) error {
+		return func(tx *sql.Tx) error {
+			_, err := Exec(ctx, db.WithTx(tx), db.Insert("t1").SetNames("id").SetValues(id))
+			return err
+		}
+	}
+	errTest := errors.New("test")
+	if err := WrapNestedTx(ctx, db, func(tx *sql.Tx) error {
+		if err := insert(1)(tx); err != nil {
+			return err
+		}
+		if err := WrapNestedTx(ctx, tx, insert(2)); err != nil {
+			return err
+		}
+		if err := WrapNestedTx(ctx, db.WithTx(tx), func(tx *sql.Tx) error {
+			if err := insert(3)(tx); err != nil {
+				return err
+			}
+			return errTest
+		}); !errors.Is(err, errTest) {
+			return fmt.Errorf("expected %v, got %v", errTest, err)
+		}
+		func() {
+			defer func() { _ = recover() }()
+			_ = WrapNestedTx(ctx, tx, func(tx *sql.Tx) error {
+				if err := insert(4)(tx); err != nil {
+					return err
+				}
+				panic("test")
+			})
+		}()
+		return WrapNestedTx(ctx, tx, func(tx *sql.Tx) error {
+			return WrapNestedTx(ctx, tx, insert(5))
+		})
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	var ids []int64
+	rows, err := db.QueryContext(ctx, `SELECT "id" FROM "t1" ORDER BY "id"`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal("Error:", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 5 {
+		t.Fatalf("Unexpected ids: %v", ids)
+	}
+	if err := WrapNestedTx(ctx, testRunner{}, insert(6)); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+type testRunner struct {
+	Runner
+}
+
+func testNewSQLiteDB(tb testing.TB) *DB {
+	cfg := SQLiteConfig{Path: filepath.Join(tb.TempDir(), "db.sqlite")}
+	db, err := cfg.NewDB()
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestWrapTxContext(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if r := RunnerFromContext(ctx, db); r != db {
+		t.Fatalf("Expected DB, got %T", r)
+	}
+	if r := db.RunnerFromContext(ctx); r != db {
+		t.Fatalf("Expected DB, got %T", r)
+	}
+	insert := func(ctx context.Context, id int) error {
+		_, err := Exec(ctx, db.RunnerFromContext(ctx), db.Insert("t1").SetNames("id").SetValues(id))
+		return err
+	}
+	count := func(ctx context.Context) int {
+		var count int
+		if err := QueryRow(
+			ctx, db.RunnerFromContext(ctx), db.Select("t1").SetExprs(Raw("COUNT(*)")),
+		).Scan(&count); err != nil {
+			t.Fatal("Error:", err)
+		}
+		return count
+	}
+	errTest := errors.New("test")
+	if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+		if _, ok := TxFromContext(ctx, db); !ok {
+			return fmt.Errorf("expected transaction in context")
+		}
+		if _, ok := RunnerFromContext(ctx, db).(*Tx); !ok {
+			return fmt.Errorf("expected *Tx runner")
+		}
+		if r := RunnerFromContext(ctx, db.DB); r != db.DB {
+			return fmt.Errorf("expected *sql.DB runner, got %T", r)
+		}
+		if err := insert(ctx, 1); err != nil {
+			return err
+		}
+		if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+			if err := insert(ctx, 2); err != nil {
+				return err
+			}
+			return errTest
+		}); !errors.Is(err, errTest) {
+			return fmt.Errorf("expected %v, got %v", errTest, err)
+		}
+		if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+			return insert(ctx, 3)
+		}); err != nil {
+			return err
+		}
+		if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+			return insert(ctx, 5)
+		}, WithReadOnly(true)); err != ErrNestedTxOptions {
+			return fmt.Errorf("expected %v, got %v", ErrNestedTxOptions, err)
+		}
+		if err := WrapNestedTx(ctx, db.RunnerFromContext(ctx), func(tx *sql.Tx) error {
+			return nil
+		}, WithRetry(RetryPolicy{})); err != ErrNestedTxOptions {
+			return fmt.Errorf("expected %v, got %v", ErrNestedTxOptions, err)
+		}
+		if c := count(ctx); c != 2 {
+			return fmt.Errorf("expected 2 rows, got %d", c)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if c := count(ctx); c != 2 {
+		t.Fatalf("Expected 2 rows, got %d", c)
+	}
+	if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+		if err := insert(ctx, 4); err != nil {
+			return err
+		}
+		return errTest
+	}); !errors.Is(err, errTest) {
+		t.Fatalf("Expected %v, got %v", errTest, err)
+	}
+	if c := count(ctx); c != 2 {
+		t.Fatalf("Expected 2 rows, got %d", c)
+	}
+}
+
+func TestWrapTxContextMultipleDB(t *testing.T) {
+	db1 := testNewSQLiteDB(t)
+	db2 := testNewSQLiteDB(t)
+	ctx := context.Background()
+	for _, db := range []*DB{db1, db2} {
+		if _, err := db.ExecContext(
+			ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY)`,
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	count := func(db *DB) int {
+		var count int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "t1"`).Scan(&count); err != nil {
+			t.Fatal("Error:", err)
+		}
+		return count
+	}
+	if err := WrapTxContext(ctx, db1, func(ctx context.Context) error {
+		if _, ok := TxFromContext(ctx, db2); ok {
+			return fmt.Errorf("unexpected transaction of another database")
+		}
+		if r := db2.RunnerFromContext(ctx); r != db2 {
+			return fmt.Errorf("expected DB, got %T", r)
+		}
+		return WrapTxContext(ctx, db2, func(ctx context.Context) error {
+			if _, err := Exec(ctx, db2.RunnerFromContext(ctx), db2.Insert("t1").SetNames("id").SetValues(1)); err != nil {
+				return err
+			}
+			tx1, _ := TxFromContext(ctx, db1)
+			tx2, _ := TxFromContext(ctx, db2)
+			if tx1 == nil || tx2 == nil || tx1.Tx == tx2.Tx {
+				return fmt.Errorf("expected separate transactions")
+			}
+			return nil
+		})
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if c := count(db1); c != 0 {
+		t.Fatalf("Expected 0 rows, got %d", c)
+	}
+	if c := count(db2); c != 1 {
+		t.Fatalf("Expected 1 row, got %d", c)
+	}
+}
+
+func TestTxHooks(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	var events []string
+	hook := func(event string) func() {
+		return func() { events = append(events, event) }
+	}
+	errTest := errors.New("test")
+	if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+		tx, ok := TxFromContext(ctx, db)
+		if !ok {
+			return fmt.Errorf("expected transaction in context")
+		}
+		tx.OnCommit(hook("commit1"))
+		tx.OnRollback(hook("rollback1"))
+		if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+			tx, _ := TxFromContext(ctx, db)
+			tx.OnCommit(hook("commit2"))
+			tx.OnRollback(hook("rollback2"))
+			return errTest
+		}); !errors.Is(err, errTest) {
+			return fmt.Errorf("expected %v, got %v", errTest, err)
+		}
+		if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+			RunnerFromContext(ctx, db).(*Tx).OnCommit(hook("commit3"))
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(events) != 1 || events[0] != "rollback2" {
+			return fmt.Errorf("unexpected events: %v", events)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	expected := []string{"rollback2", "commit1", "commit3"}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Fatalf("Expected %v, got %v", expected, events)
+	}
+	events = nil
+	func() {
+		defer func() { _ = recover() }()
+		_ = WrapTxContext(ctx, db, func(ctx context.Context) error {
+			tx, _ := TxFromContext(ctx, db)
+			tx.OnCommit(hook("commit"))
+			tx.OnRollback(hook("rollback"))
+			panic("test")
+		})
+	}()
+	if len(events) != 1 || events[0] != "rollback" {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+	if err := db.WithTx(nil).OnCommit(func() {}); err != ErrNoTxHooks {
+		t.Fatalf("Expected %v, got %v", ErrNoTxHooks, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := db.WithTx(tx).OnRollback(func() {}); err != ErrNoTxHooks {
+		t.Fatalf("Expected %v, got %v", ErrNoTxHooks, err)
+	}
+	if err := WrapNestedTx(ctx, tx, func(tx *sql.Tx) error {
+		return db.WithTx(tx).OnCommit(func() {})
+	}); err != ErrNoTxHooks {
+		t.Fatalf("Expected %v, got %v", ErrNoTxHooks, err)
+	}
+}
+
+func TestWrapTxHooks(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	var events []string
+	hook := func(event string) func() {
+		return func() { events = append(events, event) }
+	}
+	errTest := errors.New("test")
+	if err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+		if err := db.WithTx(tx).OnCommit(hook("commit1")); err != nil {
+			return err
+		}
+		if err := db.WithTx(tx).OnRollback(hook("rollback1")); err != nil {
+			return err
+		}
+		if err := WrapNestedTx(ctx, tx, func(tx *sql.Tx) error {
+			if err := db.WithTx(tx).OnCommit(hook("commit2")); err != nil {
+				return err
+			}
+			if err := db.WithTx(tx).OnRollback(hook("rollback2")); err != nil {
+				return err
+			}
+			return errTest
+		}); !errors.Is(err, errTest) {
+			return fmt.Errorf("expected %v, got %v", errTest, err)
+		}
+		if err := WrapNestedTx(ctx, db.WithTx(tx), func(tx *sql.Tx) error {
+			return db.WithTx(tx).OnCommit(hook("commit3"))
+		}); err != nil {
+			return err
+		}
+		if fmt.Sprint(events) != fmt.Sprint([]string{"rollback2"}) {
+			return fmt.Errorf("unexpected events: %v", events)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	expected := []string{"rollback2", "commit1", "commit3"}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Fatalf("Expected %v, got %v", expected, events)
+	}
+	events = nil
+	if err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+		if err := db.WithTx(tx).OnCommit(hook("commit")); err != nil {
+			return err
+		}
+		if err := db.WithTx(tx).OnRollback(hook("rollback")); err != nil {
+			return err
+		}
+		return errTest
+	}); err != errTest {
+		t.Fatalf("Expected %v, got %v", errTest, err)
+	}
+	if fmt.Sprint(events) != fmt.Sprint([]string{"rollback"}) {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+	var tx *sql.Tx
+	if err := WrapTx(ctx, db, func(v *sql.Tx) error {
+		tx = v
+		return nil
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := db.WithTx(tx).OnCommit(func() {}); err != ErrNoTxHooks {
+		t.Fatalf("Expected %v, got %v", ErrNoTxHooks, err)
+	}
+}
+
+func TestWrapTxPanicRecovery(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	errTest := errors.New("test")
+	err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+		if _, err := Exec(ctx, db.WithTx(tx), db.Insert("t1").SetNames("id").SetValues(1)); err != nil {
+			return err
+		}
+		panic(errTest)
+	}, WithPanicRecovery())
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected PanicError, got %v", err)
+	}
+	if panicErr.Value != errTest || len(panicErr.Stack) == 0 {
+		t.Fatalf("Unexpected panic error: %#v", panicErr)
+	}
+	if !errors.Is(err, errTest) {
+		t.Fatalf("Expected %v, got %v", errTest, err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "t1"`).Scan(&count); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if count != 0 {
+		t.Fatalf("Expected 0 rows, got %d", count)
+	}
+	if err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+		return errTest
+	}, WithPanicRecovery()); err != errTest {
+		t.Fatalf("Expected %v, got %v", errTest, err)
+	}
+	testExpectPanic(t, func() {
+		_ = WrapTx(ctx, db, func(tx *sql.Tx) error {
+			panic(errTest)
+		})
+	})
+	if err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+		err := WrapTxContext(ctx, db, func(ctx context.Context) error {
+			if _, err := Exec(ctx, db.RunnerFromContext(ctx), db.Insert("t1").SetNames("id").SetValues(2)); err != nil {
+				return err
+			}
+			panic(errTest)
+		}, WithPanicRecovery())
+		if !errors.As(err, &panicErr) || panicErr.RollbackErr != nil {
+			return fmt.Errorf("expected PanicError, got %v", err)
+		}
+		_, err = Exec(ctx, db.RunnerFromContext(ctx), db.Insert("t1").SetNames("id").SetValues(3))
+		return err
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "t1"`).Scan(&count); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 row, got %d", count)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	err = WrapNestedTx(ctx, tx, func(tx *sql.Tx) error {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		panic(errTest)
+	}, WithPanicRecovery())
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected PanicError, got %v", err)
+	}
+	if panicErr.RollbackErr == nil || !errors.Is(err, sql.ErrTxDone) {
+		t.Fatalf("Expected rollback error, got %v", panicErr.RollbackErr)
+	}
+	if !errors.Is(err, errTest) {
+		t.Fatalf("Expected %v, got %v", errTest, err)
+	}
+}
+
+func TestWrapTxCommitError(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+		return tx.Commit()
+	})
+	var commitErr *CommitError
+	if !errors.As(err, &commitErr) {
+		t.Fatalf("Expected CommitError, got %v", err)
+	}
+	if !errors.Is(err, sql.ErrTxDone) {
+		t.Fatalf("Expected %v, got %v", sql.ErrTxDone, err)
+	}
 }
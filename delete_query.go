@@ -1,28 +1,55 @@
 package gosql
 
 // DeleteQuery represents SQL delete query.
+//
+// Setters do not modify query and return its modified copy, so shared
+// query can be safely extended.
 type DeleteQuery interface {
 	Query
-	SetWhere(where BoolExpr)
+	SetWhere(where BoolExpr) DeleteQuery
+	// Clone returns deep copy of query.
+	Clone() DeleteQuery
+	// Table returns name of table.
+	Table() string
+	// Where returns condition of query.
+	Where() BoolExpr
 }
 
 type deleteQuery struct {
 	table string
 	where BoolExpr
+	// dialect contains dialect of builder that created query.
+	dialect queryDialect
 }
 
-func (q *deleteQuery) SetWhere(where BoolExpr) {
-	q.where = where
+func (q *deleteQuery) SetWhere(where BoolExpr) DeleteQuery {
+	clone := *q
+	clone.where = where
+	return &clone
+}
+
+func (q *deleteQuery) Clone() DeleteQuery {
+	clone := *q
+	return &clone
+}
+
+func (q deleteQuery) Table() string {
+	return q.table
+}
+
+func (q deleteQuery) Where() BoolExpr {
+	return q.where
 }
 
 func (q deleteQuery) WriteQuery(w Writer) {
+	w = q.dialect.writer(w)
 	w.WriteString("DELETE FROM ")
 	w.WriteName(q.table)
 	q.writeWhere(w)
 }
 
 func (q deleteQuery) writeWhere(w Writer) {
-	w.WriteString(" WHERE ")
+	writeClause(w, "WHERE")
 	if q.where == nil {
 		w.WriteString("1 = 1")
 		return
@@ -0,0 +1,111 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Tx represents wrapper for sql.Tx with builder for database dialect.
+type Tx struct {
+	*sql.Tx
+	// Builder contains builder for specified database dialect.
+	Builder
+}
+
+// WithTx returns wrapper for transaction with builder of database.
+//
+// Callbacks can be registered with OnCommit and OnRollback only when
+// transaction is started by WrapTx, WrapTxContext or WrapNestedTx.
+func (d *DB) WithTx(tx *sql.Tx) *Tx {
+	return &Tx{Tx: tx, Builder: d.Builder}
+}
+
+// ExecQuery builds and executes query that doesn't return rows.
+func (d *DB) ExecQuery(ctx context.Context, query Query) (sql.Result, error) {
+	return Exec(ctx, d, query)
+}
+
+// QueryAll builds and executes query that returns rows.
+func (d *DB) QueryAll(ctx context.Context, query Query) (*sql.Rows, error) {
+	return QueryRows(ctx, d, query)
+}
+
+// QueryRowQuery builds and executes query that is expected to return
+// at most one row.
+func (d *DB) QueryRowQuery(ctx context.Context, query Query) *sql.Row {
+	return QueryRow(ctx, d, query)
+}
+
+// ExecQuery builds and executes query that doesn't return rows.
+func (t *Tx) ExecQuery(ctx context.Context, query Query) (sql.Result, error) {
+	return Exec(ctx, t, query)
+}
+
+// QueryAll builds and executes query that returns rows.
+func (t *Tx) QueryAll(ctx context.Context, query Query) (*sql.Rows, error) {
+	return QueryRows(ctx, t, query)
+}
+
+// QueryRowQuery builds and executes query that is expected to return
+// at most one row.
+func (t *Tx) QueryRowQuery(ctx context.Context, query Query) *sql.Row {
+	return QueryRow(ctx, t, query)
+}
+
+// QueryRunner represents runner that builds queries for dialect of its
+// connection like DB or Tx.
+type QueryRunner interface {
+	Runner
+	// Build renders query string and values.
+	Build(query Query) (string, []any)
+}
+
+// Exec builds and executes query that doesn't return rows.
+func Exec(ctx context.Context, r QueryRunner, query Query) (sql.Result, error) {
+	rawQuery, values, err := buildQuery(r, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.ExecContext(ctx, rawQuery, values...)
+}
+
+// QueryRows builds and executes query that returns rows.
+func QueryRows(ctx context.Context, r QueryRunner, query Query) (*sql.Rows, error) {
+	rawQuery, values, err := buildQuery(r, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.QueryContext(ctx, rawQuery, values...)
+}
+
+// QueryRow builds and executes query that is expected to return at most
+// one row.
+//
+// Since sql.Row can not be created with error, QueryRow panics when query
+// can not be built. Use QueryRows to get such errors like ErrNoTenant.
+func QueryRow(ctx context.Context, r QueryRunner, query Query) *sql.Row {
+	rawQuery, values := r.Build(query)
+	return r.QueryRowContext(ctx, rawQuery, values...)
+}
+
+// buildQuery builds query and returns errors of builders like
+// TenantBuilder instead of panic.
+func buildQuery(r QueryRunner, query Query) (rawQuery string, values []any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			buildErr, ok := v.(buildError)
+			if !ok {
+				panic(v)
+			}
+			err = buildErr.err
+		}
+	}()
+	rawQuery, values = r.Build(query)
+	return rawQuery, values, nil
+}
+
+// Test *DB and *Tx for interfaces.
+var (
+	_ QueryRunner = &DB{}
+	_ QueryRunner = &Tx{}
+)
@@ -0,0 +1,52 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestExec(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY, "name" TEXT)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := db.ExecQuery(ctx, db.Insert("t1").SetNames("id", "name").SetValues(1, "a")); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+		_, err := Exec(ctx, db.WithTx(tx), db.Insert("t1").SetNames("id", "name").SetValues(2, "b"))
+		return err
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	rows, err := db.QueryAll(ctx, db.Select("t1").SetNames("name").SetOrderBy("id"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal("Error:", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	_ = rows.Close()
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("Unexpected names: %v", names)
+	}
+	var name string
+	if err := db.QueryRowQuery(ctx, db.Select("t1").SetNames("name").SetWhere(Column("id").Equal(2))).Scan(&name); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if name != "b" {
+		t.Fatalf("Expected %q, got %q", "b", name)
+	}
+}
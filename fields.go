@@ -0,0 +1,193 @@
+package gosql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField represents struct field mapped to column.
+type structField struct {
+	// name contains name of column.
+	name string
+	// index contains index sequence for reflect.Value.FieldByIndex.
+	index []int
+	// omitEmpty specifies that zero value should not be written.
+	omitEmpty bool
+	// readOnly specifies that value should never be written.
+	readOnly bool
+}
+
+// structFields represents mapping of struct fields to columns.
+type structFields struct {
+	list   []structField
+	byName map[string]int
+}
+
+// fieldByName returns field by name of column.
+func (f *structFields) fieldByName(name string) (structField, bool) {
+	i, ok := f.byName[name]
+	if !ok {
+		return structField{}, false
+	}
+	return f.list[i], true
+}
+
+// names returns list of column names.
+func (f *structFields) names() []string {
+	names := make([]string, len(f.list))
+	for i, field := range f.list {
+		names[i] = field.name
+	}
+	return names
+}
+
+var structFieldsCache sync.Map
+
+// getStructFields returns mapping of struct fields to columns.
+//
+// Fields are mapped using tags like `db:"name,options"`. Fields without
+// tag are ignored except embedded structs which fields are mapped as
+// fields of parent struct.
+//
+// Supported options:
+//   - omitempty: zero value is not written;
+//   - readonly or autoincrement: value is never written.
+func getStructFields(typ reflect.Type) *structFields {
+	if fields, ok := structFieldsCache.Load(typ); ok {
+		return fields.(*structFields)
+	}
+	fields := structFields{byName: map[string]int{}}
+	collectStructFields(&fields, typ, nil)
+	actual, _ := structFieldsCache.LoadOrStore(typ, &fields)
+	return actual.(*structFields)
+}
+
+func collectStructFields(fields *structFields, typ reflect.Type, index []int) {
+	var embedded []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("db")
+		if !ok {
+			if field.Anonymous {
+				embedded = append(embedded, field)
+			}
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" || name == "" || !field.IsExported() {
+			continue
+		}
+		if _, ok := fields.byName[name]; ok {
+			// Fields of outer struct have priority.
+			continue
+		}
+		structField := structField{
+			name:  name,
+			index: append(index[:len(index):len(index)], i),
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				structField.omitEmpty = true
+			case "readonly", "autoincrement":
+				structField.readOnly = true
+			}
+		}
+		fields.byName[name] = len(fields.list)
+		fields.list = append(fields.list, structField)
+	}
+	for _, field := range embedded {
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			if !field.IsExported() {
+				// Pointer to unexported struct cannot be allocated.
+				continue
+			}
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			collectStructFields(
+				fields, fieldType, append(index[:len(index):len(index)], field.Index...),
+			)
+		}
+	}
+}
+
+// fieldByIndex returns struct field by index allocating nil embedded
+// pointers to structs.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// lookupFieldByIndex returns struct field by index and false if any
+// of embedded pointers to structs is nil.
+func lookupFieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// rowValues returns names and values of writable struct fields.
+//
+// When columns are specified, only these columns are returned and
+// omitempty option is ignored.
+func rowValues(row any, columns []string) ([]string, []any) {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Errorf("expected struct but got %T", row))
+	}
+	fields := getStructFields(v.Type())
+	var names []string
+	var values []any
+	if len(columns) > 0 {
+		for _, column := range columns {
+			field, ok := fields.fieldByName(column)
+			if !ok {
+				panic(fmt.Errorf("column %q is not mapped to %v", column, v.Type()))
+			}
+			if field.readOnly {
+				panic(fmt.Errorf("column %q of %v is read-only", column, v.Type()))
+			}
+			names = append(names, column)
+			if value, ok := lookupFieldByIndex(v, field.index); ok {
+				values = append(values, value.Interface())
+			} else {
+				values = append(values, nil)
+			}
+		}
+		return names, values
+	}
+	for _, field := range fields.list {
+		if field.readOnly {
+			continue
+		}
+		value, ok := lookupFieldByIndex(v, field.index)
+		if !ok || (field.omitEmpty && value.IsZero()) {
+			continue
+		}
+		names = append(names, field.name)
+		values = append(values, value.Interface())
+	}
+	return names, values
+}
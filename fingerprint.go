@@ -0,0 +1,28 @@
+package gosql
+
+import (
+	"hash/fnv"
+)
+
+// fingerprintBuilder is used for rendering of fingerprints, so they are
+// the same for queries built for any dialect.
+var fingerprintBuilder = builder{dialect: PostgresDialect}
+
+// Fingerprint returns normalized query string that can be used for
+// grouping of queries by shape.
+//
+// All values are replaced with "?" and lists of IN expressions are
+// replaced with "...", so queries that differ only in values have the
+// same fingerprint.
+func Fingerprint(query Query) string {
+	builder := &writer{builder: &fingerprintBuilder, fingerprint: true}
+	query.WriteQuery(builder)
+	return builder.String()
+}
+
+// FingerprintHash returns 64-bit FNV-1a hash of query fingerprint.
+func FingerprintHash(query Query) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(Fingerprint(query)))
+	return hash.Sum64()
+}
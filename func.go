@@ -0,0 +1,152 @@
+package gosql
+
+import (
+	"fmt"
+)
+
+type funcKind int
+
+const (
+	customFunc funcKind = iota
+	nowFunc
+	stringAggFunc
+	substringFunc
+)
+
+type funcExpr struct {
+	kind funcKind
+	name string
+	args []Value
+}
+
+// Func creates a new function call expression: "name(args...)".
+//
+// Name of function is written as is, so it should not be taken from
+// untrusted input. Arguments that are not expressions are passed as
+// query values.
+func Func(name string, args ...any) Value {
+	return newFunc(customFunc, name, args...)
+}
+
+// Coalesce build expression: "COALESCE(args...)".
+func Coalesce(args ...any) Value {
+	return Func("COALESCE", args...)
+}
+
+// Lower build expression: "LOWER(arg)".
+func Lower(arg any) Value {
+	return Func("LOWER", arg)
+}
+
+// Upper build expression: "UPPER(arg)".
+func Upper(arg any) Value {
+	return Func("UPPER", arg)
+}
+
+// Length build expression: "LENGTH(arg)".
+func Length(arg any) Value {
+	return Func("LENGTH", arg)
+}
+
+// Abs build expression: "ABS(arg)".
+func Abs(arg any) Value {
+	return Func("ABS", arg)
+}
+
+// Now build expression that returns current timestamp.
+//
+// For SQLite it is rendered as "CURRENT_TIMESTAMP" and for Postgres
+// as "NOW()".
+func Now() Value {
+	return newFunc(nowFunc, "NOW")
+}
+
+// StringAgg build aggregate expression that concatenates values
+// with separator.
+//
+// For SQLite it is rendered as "GROUP_CONCAT(arg, sep)" and for Postgres
+// as "STRING_AGG(arg, sep)".
+func StringAgg(arg any, sep any) Value {
+	return newFunc(stringAggFunc, "STRING_AGG", arg, sep)
+}
+
+// Substring build expression that extracts substring of specified
+// length starting from specified position.
+//
+// For SQLite it is rendered as "SUBSTR(arg, from, length)" and for
+// Postgres as "SUBSTRING(arg, from, length)".
+func Substring(arg any, from any, length any) Value {
+	return newFunc(substringFunc, "SUBSTRING", arg, from, length)
+}
+
+func newFunc(kind funcKind, name string, args ...any) funcExpr {
+	expr := funcExpr{kind: kind, name: name}
+	for _, arg := range args {
+		expr.args = append(expr.args, wrapValue(arg))
+	}
+	return expr
+}
+
+func (e funcExpr) Equal(o any) BoolExpr {
+	return cmp{kind: eqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e funcExpr) NotEqual(o any) BoolExpr {
+	return cmp{kind: notEqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e funcExpr) Less(o any) BoolExpr {
+	return cmp{kind: lessCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e funcExpr) Greater(o any) BoolExpr {
+	return cmp{kind: greaterCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e funcExpr) LessEqual(o any) BoolExpr {
+	return cmp{kind: lessEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e funcExpr) GreaterEqual(o any) BoolExpr {
+	return cmp{kind: greaterEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e funcExpr) In(o ...any) BoolExpr {
+	return newIn(e, false, o)
+}
+
+func (e funcExpr) NotIn(o ...any) BoolExpr {
+	return newIn(e, true, o)
+}
+
+func (e funcExpr) WriteExpr(w Writer) {
+	name := e.name
+	dialect, _ := writerDialect(w)
+	switch e.kind {
+	case customFunc:
+	case nowFunc:
+		if dialect == SQLiteDialect {
+			w.WriteString("CURRENT_TIMESTAMP")
+			return
+		}
+	case stringAggFunc:
+		if dialect == SQLiteDialect {
+			name = "GROUP_CONCAT"
+		}
+	case substringFunc:
+		if dialect == SQLiteDialect {
+			name = "SUBSTR"
+		}
+	default:
+		panic(fmt.Errorf("unsupported function: %d", e.kind))
+	}
+	w.WriteString(name)
+	w.WriteRune('(')
+	for i, arg := range e.args {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		arg.WriteExpr(w)
+	}
+	w.WriteRune(')')
+}
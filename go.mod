@@ -1,5 +1,5 @@
 module github.com/udovin/gosql
 
-go 1.18
+go 1.20
 
 require github.com/mattn/go-sqlite3 v1.14.16
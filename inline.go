@@ -0,0 +1,176 @@
+package gosql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inlineMarker is written before inline query to mark it as rendered for
+// logging. It is a plain comment, so it does not prevent execution.
+const inlineMarker = "/* not for execution */ "
+
+// InlineBuilder represents builder that formats queries with values
+// written as literals.
+type InlineBuilder interface {
+	// BuildInline formats query string with values written as literals.
+	//
+	// Result is intended for logging and debugging only and should never
+	// be executed.
+	BuildInline(query Query) string
+}
+
+// BuildInline formats query string with values written as literals.
+//
+// When builder does not implement InlineBuilder, builder of the same
+// dialect without options is used.
+func BuildInline(b Builder, query Query) string {
+	if ib, ok := b.(InlineBuilder); ok {
+		return ib.BuildInline(query)
+	}
+	return NewBuilder(b.Dialect()).(InlineBuilder).BuildInline(query)
+}
+
+// Test builders for interfaces.
+var (
+	_ InlineBuilder = &builder{}
+	_ InlineBuilder = &TenantBuilder{}
+	_ InlineBuilder = &SoftDeleteBuilder{}
+)
+
+// WithRedactor represents option that specifies function that replaces
+// values of sensitive columns for BuildInline.
+//
+// Column is specified for values of inserted and updated columns and for
+// values compared with bare Column, for example with Column("t.password")
+// column is "t.password". Column is empty for other values, for example
+// when column is wrapped into function like Lower(Column("password")).
+func WithRedactor(redact func(column string, value any) any) BuilderOption {
+	return func(b *builder) {
+		b.redact = redact
+	}
+}
+
+// writeColumnExpr writes expression that is compared with or assigned to
+// specified column.
+func writeColumnExpr(w Writer, column string, expr Expr) {
+	if wr, ok := w.(*writer); ok {
+		prev := wr.column
+		wr.column = column
+		defer func() { wr.column = prev }()
+	}
+	expr.WriteExpr(w)
+}
+
+func (w *writer) writeInlineValue(value any) {
+	if w.builder.redact != nil {
+		value = w.builder.redact(w.column, value)
+	}
+	w.writeLiteral(value)
+}
+
+func (w *writer) writeLiteral(value any) {
+	switch v := value.(type) {
+	case nil:
+		w.query.WriteString("NULL")
+		return
+	case Param:
+		w.query.WriteRune(':')
+		w.query.WriteString(string(v))
+		return
+	case driver.Valuer:
+		if isNilPointer(v) {
+			w.query.WriteString("NULL")
+			return
+		}
+		inner, err := v.Value()
+		if err != nil {
+			w.writeString(fmt.Sprintf("<error: %v>", err))
+			return
+		}
+		w.writeLiteral(inner)
+		return
+	case []byte:
+		w.writeBytes(v)
+		return
+	case time.Time:
+		w.writeString(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+		return
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			w.query.WriteString("NULL")
+			return
+		}
+		w.writeLiteral(rv.Elem().Interface())
+	case reflect.Bool:
+		w.writeBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.query.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		w.query.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		w.writeFloat(rv.Float())
+	case reflect.String:
+		w.writeString(rv.String())
+	default:
+		w.writeString(fmt.Sprint(value))
+	}
+}
+
+func (w *writer) writeBool(v bool) {
+	switch w.builder.dialect {
+	case SQLiteDialect:
+		if v {
+			w.query.WriteRune('1')
+		} else {
+			w.query.WriteRune('0')
+		}
+	default:
+		if v {
+			w.query.WriteString("TRUE")
+		} else {
+			w.query.WriteString("FALSE")
+		}
+	}
+}
+
+func (w *writer) writeFloat(v float64) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		// Special values can be represented only as strings.
+		w.writeString(strconv.FormatFloat(v, 'g', -1, 64))
+		return
+	}
+	w.query.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (w *writer) writeString(v string) {
+	w.query.WriteRune('\'')
+	w.query.WriteString(strings.ReplaceAll(v, "'", "''"))
+	w.query.WriteRune('\'')
+}
+
+func (w *writer) writeBytes(v []byte) {
+	switch w.builder.dialect {
+	case SQLiteDialect:
+		w.query.WriteString("X'")
+		w.query.WriteString(hex.EncodeToString(v))
+		w.query.WriteRune('\'')
+	default:
+		w.query.WriteString("'\\x")
+		w.query.WriteString(hex.EncodeToString(v))
+		w.query.WriteString("'::bytea")
+	}
+}
+
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
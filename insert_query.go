@@ -5,30 +5,96 @@ import (
 )
 
 // InsertQuery represents SQL insert query.
+//
+// Setters do not modify query and return its modified copy, so shared
+// query can be safely extended.
 type InsertQuery interface {
 	Query
-	SetNames(name ...string)
-	SetValues(values ...any)
+	SetNames(name ...string) InsertQuery
+	SetValues(values ...any) InsertQuery
+	// SetRow sets names and values from fields of struct tagged like
+	// `db:"name"`.
+	//
+	// When columns are specified, only these columns are inserted.
+	SetRow(row any, columns ...string) InsertQuery
+	// Clone returns deep copy of query.
+	Clone() InsertQuery
+	// Table returns name of table.
+	Table() string
+	// Names returns list of inserted columns.
+	Names() []string
+	// Values returns list of inserted values.
+	Values() []Value
 }
 
 type insertQuery struct {
 	table  string
 	names  []string
 	values []Value
+	// dialect contains dialect of builder that created query.
+	dialect queryDialect
 }
 
-func (q *insertQuery) SetNames(names ...string) {
+func (q *insertQuery) SetNames(names ...string) InsertQuery {
+	clone := *q
+	clone.setNames(names)
+	return &clone
+}
+
+func (q *insertQuery) SetValues(values ...any) InsertQuery {
+	clone := *q
+	clone.setValues(values)
+	return &clone
+}
+
+func (q *insertQuery) SetRow(row any, columns ...string) InsertQuery {
+	clone := *q
+	clone.setRow(row, columns)
+	return &clone
+}
+
+func (q *insertQuery) Clone() InsertQuery {
+	clone := q.clone()
+	return &clone
+}
+
+func (q *insertQuery) setNames(names []string) {
 	q.names = names
 }
 
-func (q *insertQuery) SetValues(values ...any) {
+func (q *insertQuery) setValues(values []any) {
 	q.values = nil
 	for _, val := range values {
 		q.values = append(q.values, wrapValue(val))
 	}
 }
 
+func (q *insertQuery) setRow(row any, columns []string) {
+	names, values := rowValues(row, columns)
+	q.setNames(names)
+	q.setValues(values)
+}
+
+func (q insertQuery) clone() insertQuery {
+	q.names = append([]string(nil), q.names...)
+	q.values = append([]Value(nil), q.values...)
+	return q
+}
+
+func (q insertQuery) Table() string {
+	return q.table
+}
+
+func (q insertQuery) Names() []string {
+	return append([]string(nil), q.names...)
+}
+
+func (q insertQuery) Values() []Value {
+	return append([]Value(nil), q.values...)
+}
+
 func (q insertQuery) WriteQuery(w Writer) {
+	w = q.dialect.writer(w)
 	w.WriteString("INSERT INTO ")
 	w.WriteName(q.table)
 	q.writeInsert(w)
@@ -48,12 +114,14 @@ func (q insertQuery) writeInsert(w Writer) {
 		}
 		w.WriteName(name)
 	}
-	w.WriteString(") VALUES (")
+	w.WriteRune(')')
+	writeClause(w, "VALUES")
+	w.WriteRune('(')
 	for i, value := range q.values {
 		if i > 0 {
 			w.WriteString(", ")
 		}
-		value.WriteExpr(w)
+		writeColumnExpr(w, q.names[i], value)
 	}
 	w.WriteRune(')')
 }
@@ -63,20 +131,50 @@ type PostgresInsertQuery struct {
 	returning []string
 }
 
-func (q *PostgresInsertQuery) SetReturning(names ...string) {
-	q.returning = names
+func (q *PostgresInsertQuery) SetNames(names ...string) InsertQuery {
+	clone := *q
+	clone.setNames(names)
+	return &clone
+}
+
+func (q *PostgresInsertQuery) SetValues(values ...any) InsertQuery {
+	clone := *q
+	clone.setValues(values)
+	return &clone
+}
+
+func (q *PostgresInsertQuery) SetRow(row any, columns ...string) InsertQuery {
+	clone := *q
+	clone.setRow(row, columns)
+	return &clone
+}
+
+func (q *PostgresInsertQuery) SetReturning(names ...string) *PostgresInsertQuery {
+	clone := *q
+	clone.returning = names
+	return &clone
+}
+
+func (q *PostgresInsertQuery) Clone() InsertQuery {
+	return &PostgresInsertQuery{
+		insertQuery: q.insertQuery.clone(),
+		returning:   append([]string(nil), q.returning...),
+	}
+}
+
+// Returning returns list of returned columns.
+func (q PostgresInsertQuery) Returning() []string {
+	return append([]string(nil), q.returning...)
 }
 
 func (q PostgresInsertQuery) WriteQuery(w Writer) {
-	wr, ok := w.(*writer)
+	w = q.dialect.writer(w)
+	d, ok := writerDialect(w)
 	if !ok {
 		panic(fmt.Errorf("required postgres writer"))
 	}
-	if wr.builder.dialect != PostgresDialect {
-		panic(fmt.Errorf(
-			"required postgres writer but got %q",
-			wr.builder.dialect,
-		))
+	if d != PostgresDialect {
+		panic(fmt.Errorf("required postgres writer but got %d", d))
 	}
 	q.insertQuery.WriteQuery(w)
 	q.writeReturning(w)
@@ -84,7 +182,7 @@ func (q PostgresInsertQuery) WriteQuery(w Writer) {
 
 func (q PostgresInsertQuery) writeReturning(w Writer) {
 	if len(q.returning) > 0 {
-		w.WriteString(" RETURNING ")
+		writeClause(w, "RETURNING")
 		for i, name := range q.returning {
 			if i > 0 {
 				w.WriteString(", ")
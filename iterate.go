@@ -0,0 +1,36 @@
+//go:build go1.23
+
+package gosql
+
+import (
+	"context"
+	"iter"
+)
+
+// Iterate returns sequence of rows of select query scanned into structs.
+//
+// Query is executed when iteration starts and rows are scanned lazily.
+// Rows are closed when iteration finishes, including early break. Error
+// of query or scanning is yielded as the last element of sequence.
+func Iterate[T any](
+	ctx context.Context, r QueryRunner, query SelectQuery, options ...ScanOption,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var empty T
+		cursor, err := NewCursor[T](ctx, r, query, options...)
+		if err != nil {
+			yield(empty, err)
+			return
+		}
+		defer func() { _ = cursor.Close() }()
+		for cursor.Next() {
+			row, err := cursor.Scan()
+			if !yield(row, err) || err != nil {
+				return
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			yield(empty, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+//go:build go1.23
+
+package gosql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIterate(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	testPrepareScanTable(t, db)
+	ctx := context.Background()
+	var names []string
+	for row, err := range Iterate[testScanRow](ctx, db, db.Select("t1").SetOrderBy("id")) {
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		names = append(names, row.Name)
+	}
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("Unexpected names: %v", names)
+	}
+	for row, err := range Iterate[testScanRow](ctx, db, db.Select("t1").SetOrderBy("id")) {
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if row.ID != 1 {
+			t.Fatalf("Unexpected row: %+v", row)
+		}
+		break
+	}
+	if n := db.DB.Stats().InUse; n != 0 {
+		t.Fatalf("Expected rows to be closed, got %d connections in use", n)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM "t1" WHERE "id" = 1`); err != nil {
+		t.Fatal("Error:", err)
+	}
+	count := 0
+	for _, err := range Iterate[testScanRow](
+		ctx, db, db.Select("t1").SetNames("id"), WithStrictColumns(true),
+	) {
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("Expected single error, got %d elements", count)
+	}
+}
@@ -0,0 +1,100 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrConcurrentModification represents error when row was modified
+// concurrently and its version does not match expected one.
+var ErrConcurrentModification = errors.New("concurrent modification")
+
+// ErrRowNotFound represents error when updated row does not exist.
+//
+// It wraps sql.ErrNoRows, so both errors can be checked with errors.Is.
+var ErrRowNotFound = fmt.Errorf("row not found: %w", sql.ErrNoRows)
+
+// ConcurrentModificationError represents error when version of updated
+// row does not match expected one.
+//
+// It matches ErrConcurrentModification with errors.Is.
+type ConcurrentModificationError struct {
+	// Table contains name of updated table.
+	Table string
+	// Where contains condition of update query without version predicate.
+	Where BoolExpr
+	// Version contains expected version of row.
+	Version any
+}
+
+func (e *ConcurrentModificationError) Error() string {
+	return fmt.Sprintf(
+		"%v: table %q has no row with version %v",
+		ErrConcurrentModification, e.Table, e.Version,
+	)
+}
+
+// Is reports whether target is ErrConcurrentModification.
+func (e *ConcurrentModificationError) Is(target error) bool {
+	return target == ErrConcurrentModification
+}
+
+// UpdateWithVersion executes update query with optimistic locking.
+//
+// Query is extended with predicate "column = version" and assignment
+// "column = column + 1". When no rows were updated, existence of row is
+// checked with condition of query: ErrRowNotFound is returned for missing
+// row and *ConcurrentModificationError is returned otherwise.
+func UpdateWithVersion(
+	ctx context.Context,
+	r QueryRunner,
+	query UpdateQuery,
+	column string,
+	version any,
+) error {
+	where := query.Where()
+	query = setUpdateValue(query.Clone(), column, Raw("? + 1", Column(column)))
+	query = query.SetWhere(andWhere(where, Column(column).Equal(version)))
+	result, err := Exec(ctx, r, query)
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	exists, err := rowExists(ctx, r, query.Table(), where)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrRowNotFound
+	}
+	return &ConcurrentModificationError{
+		Table:   query.Table(),
+		Where:   where,
+		Version: version,
+	}
+}
+
+// rowExists reports whether table contains row that matches condition.
+func rowExists(ctx context.Context, r QueryRunner, table string, where BoolExpr) (bool, error) {
+	query := (&selectQuery{table: table}).
+		SetExprs(Raw("1")).
+		SetWhere(where).
+		SetLimit(1)
+	rows, err := QueryRows(ctx, r, query)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	return true, rows.Close()
+}
@@ -0,0 +1,62 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestUpdateWithVersion(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY, "name" TEXT, "version" INTEGER)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := db.ExecContext(
+		ctx, `INSERT INTO "t1" ("id", "name", "version") VALUES (1, 'a', 1)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	query := db.Update("t1").SetNames("name").SetValues("b").SetWhere(Column("id").Equal(1))
+	if err := UpdateWithVersion(ctx, db, query, "version", 1); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if s := db.BuildString(query); s != `UPDATE "t1" SET "name" = $1 WHERE "id" = $2` {
+		t.Fatalf("Query should not be changed: %q", s)
+	}
+	var name string
+	var version int
+	if err := db.QueryRowContext(
+		ctx, `SELECT "name", "version" FROM "t1" WHERE "id" = 1`,
+	).Scan(&name, &version); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if name != "b" || version != 2 {
+		t.Fatalf("Unexpected row: %q, %d", name, version)
+	}
+	err := UpdateWithVersion(ctx, db, query, "version", 1)
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("Expected %v, got %v", ErrConcurrentModification, err)
+	}
+	var modErr *ConcurrentModificationError
+	if !errors.As(err, &modErr) {
+		t.Fatalf("Expected ConcurrentModificationError, got %v", err)
+	}
+	if modErr.Table != "t1" || modErr.Version != 1 {
+		t.Fatalf("Unexpected error: %#v", modErr)
+	}
+	if errors.Is(err, ErrRowNotFound) {
+		t.Fatalf("Unexpected %v", ErrRowNotFound)
+	}
+	missing := db.Update("t1").SetNames("name").SetValues("c").SetWhere(Column("id").Equal(2))
+	err = UpdateWithVersion(ctx, db, missing, "version", 1)
+	if !errors.Is(err, ErrRowNotFound) || !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected %v, got %v", ErrRowNotFound, err)
+	}
+	if errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("Unexpected %v", ErrConcurrentModification)
+	}
+}
@@ -0,0 +1,119 @@
+package gosql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// Param represents named parameter that is bound after query is built.
+//
+// Built query contains Param in place of value, so the same query string
+// can be reused with different arguments using Bind.
+type Param string
+
+// Equal build boolean expression: "param = value".
+func (p Param) Equal(o any) BoolExpr {
+	return cmp{kind: eqCmp, lhs: p, rhs: wrapValue(o)}
+}
+
+// NotEqual build boolean expression: "param <> value".
+func (p Param) NotEqual(o any) BoolExpr {
+	return cmp{kind: notEqCmp, lhs: p, rhs: wrapValue(o)}
+}
+
+// Less build boolean expression: "param < value".
+func (p Param) Less(o any) BoolExpr {
+	return cmp{kind: lessCmp, lhs: p, rhs: wrapValue(o)}
+}
+
+// Greater build boolean expression: "param > value".
+func (p Param) Greater(o any) BoolExpr {
+	return cmp{kind: greaterCmp, lhs: p, rhs: wrapValue(o)}
+}
+
+// LessEqual build boolean expression: "param <= value".
+func (p Param) LessEqual(o any) BoolExpr {
+	return cmp{kind: lessEqualCmp, lhs: p, rhs: wrapValue(o)}
+}
+
+// GreaterEqual build boolean expression: "param >= value".
+func (p Param) GreaterEqual(o any) BoolExpr {
+	return cmp{kind: greaterEqualCmp, lhs: p, rhs: wrapValue(o)}
+}
+
+// In build boolean expression: "param IN (values...)".
+func (p Param) In(o ...any) BoolExpr {
+	return newIn(p, false, o)
+}
+
+// NotIn build boolean expression: "param NOT IN (values...)".
+func (p Param) NotIn(o ...any) BoolExpr {
+	return newIn(p, true, o)
+}
+
+func (p Param) WriteExpr(w Writer) {
+	w.WriteValue(p)
+}
+
+// Value implements driver.Valuer and always returns error, so query
+// with parameters cannot be executed without Bind.
+func (p Param) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("parameter %q is not bound", string(p))
+}
+
+// Bind returns values of built query with parameters replaced by
+// arguments.
+//
+// Arguments can be specified as map[string]any or as struct (or pointer
+// to struct) with fields tagged like `db:"name"` including fields of
+// embedded structs.
+func Bind(values []any, args any) ([]any, error) {
+	lookup, err := newParamLookup(args)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, len(values))
+	for i, value := range values {
+		param, ok := value.(Param)
+		if !ok {
+			result[i] = value
+			continue
+		}
+		arg, ok := lookup(string(param))
+		if !ok {
+			return nil, fmt.Errorf("parameter %q is not specified", param)
+		}
+		result[i] = arg
+	}
+	return result, nil
+}
+
+func newParamLookup(args any) (func(string) (any, bool), error) {
+	if m, ok := args.(map[string]any); ok {
+		return func(name string) (any, bool) {
+			arg, ok := m[name]
+			return arg, ok
+		}, nil
+	}
+	v := reflect.ValueOf(args)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported arguments type: %T", args)
+	}
+	fields := getStructFields(v.Type())
+	return func(name string) (any, bool) {
+		field, ok := fields.fieldByName(name)
+		if !ok {
+			return nil, false
+		}
+		value, ok := lookupFieldByIndex(v, field.index)
+		if !ok {
+			// Field of nil embedded struct is treated as NULL.
+			return nil, true
+		}
+		return value.Interface(), true
+	}, nil
+}
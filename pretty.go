@@ -0,0 +1,41 @@
+package gosql
+
+import (
+	"strings"
+)
+
+// prettyIndent is used for indentation of broken boolean expressions.
+const prettyIndent = "  "
+
+func isPrettyPrint(w Writer) bool {
+	wr, ok := w.(*writer)
+	return ok && wr.builder.prettyPrint
+}
+
+// writeClause writes clause keyword of query.
+//
+// With pretty print each clause starts with a new line.
+func writeClause(w Writer, keyword string) {
+	if isPrettyPrint(w) {
+		w.WriteRune('\n')
+	} else {
+		w.WriteRune(' ')
+	}
+	w.WriteString(keyword)
+	w.WriteRune(' ')
+}
+
+// writeOperator writes operator of boolean expression.
+//
+// With pretty print each operand starts with a new line indented
+// according to nesting level of expression.
+func writeOperator(w Writer, operator string) {
+	if wr, ok := w.(*writer); ok && wr.builder.prettyPrint {
+		w.WriteRune('\n')
+		w.WriteString(strings.Repeat(prettyIndent, wr.depth+1))
+	} else {
+		w.WriteRune(' ')
+	}
+	w.WriteString(operator)
+	w.WriteRune(' ')
+}
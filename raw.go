@@ -0,0 +1,94 @@
+package gosql
+
+import (
+	"strings"
+)
+
+// RawExpr represents raw SQL fragment with parameters.
+type RawExpr struct {
+	query string
+	args  []any
+}
+
+// Raw creates a new raw SQL fragment.
+//
+// Each "?" in query is replaced with corresponding argument: expressions
+// are written as is and other arguments are passed as query values.
+// Use "??" to write literal "?" character.
+//
+// Query is written without any escaping, so it should not be taken from
+// untrusted input.
+func Raw(query string, args ...any) RawExpr {
+	return RawExpr{query: query, args: args}
+}
+
+func (e RawExpr) Or(o BoolExpr) BoolExpr {
+	return binaryExpr{kind: orExpr, lhs: e, rhs: o}
+}
+
+func (e RawExpr) And(o BoolExpr) BoolExpr {
+	return binaryExpr{kind: andExpr, lhs: e, rhs: o}
+}
+
+func (e RawExpr) Equal(o any) BoolExpr {
+	return cmp{kind: eqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e RawExpr) NotEqual(o any) BoolExpr {
+	return cmp{kind: notEqCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e RawExpr) Less(o any) BoolExpr {
+	return cmp{kind: lessCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e RawExpr) Greater(o any) BoolExpr {
+	return cmp{kind: greaterCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e RawExpr) LessEqual(o any) BoolExpr {
+	return cmp{kind: lessEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e RawExpr) GreaterEqual(o any) BoolExpr {
+	return cmp{kind: greaterEqualCmp, lhs: e, rhs: wrapValue(o)}
+}
+
+func (e RawExpr) In(o ...any) BoolExpr {
+	return newIn(e, false, o)
+}
+
+func (e RawExpr) NotIn(o ...any) BoolExpr {
+	return newIn(e, true, o)
+}
+
+func (e RawExpr) WriteExpr(w Writer) {
+	query := e.query
+	args := e.args
+	for {
+		pos := strings.IndexRune(query, '?')
+		if pos < 0 {
+			break
+		}
+		w.WriteString(query[:pos])
+		if strings.HasPrefix(query[pos+1:], "?") {
+			w.WriteRune('?')
+			query = query[pos+2:]
+			continue
+		}
+		if len(args) == 0 {
+			panic("amount of placeholders and arguments differs")
+		}
+		if arg, ok := args[0].(Expr); ok {
+			arg.WriteExpr(w)
+		} else {
+			w.WriteValue(args[0])
+		}
+		args = args[1:]
+		query = query[pos+1:]
+	}
+	if len(args) > 0 {
+		panic("amount of placeholders and arguments differs")
+	}
+	w.WriteString(query)
+}
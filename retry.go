@@ -0,0 +1,97 @@
+package gosql
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy represents policy of retrying transactions.
+type RetryPolicy struct {
+	// MaxAttempts contains maximal amount of attempts including the first
+	// one. Default value is 3.
+	MaxAttempts int
+	// MinDelay contains delay before the second attempt. Each next delay
+	// is doubled. Default value is 10ms.
+	MinDelay time.Duration
+	// MaxDelay contains maximal delay between attempts. Default value
+	// is 1s.
+	MaxDelay time.Duration
+	// Retryable reports whether transaction should be retried after
+	// specified error. IsRetryableError is used by default.
+	Retryable func(err error) bool
+}
+
+// WithRetry represents option for WrapTx that reruns whole transaction
+// when it fails with retryable error.
+//
+// Delay between attempts grows exponentially with random jitter. When
+// context is canceled during delay, the last error is returned.
+func WithRetry(policy RetryPolicy) BeginTxOption {
+	return withTxOptions(func(opts *txOptions) {
+		opts.retry = &policy
+	})
+}
+
+// sqlStateError represents driver error with SQLSTATE code like
+// *pgconn.PgError of pgx or *pq.Error.
+type sqlStateError interface {
+	SQLState() string
+}
+
+// IsRetryableError reports whether transaction that failed with
+// specified error can be retried.
+//
+// Postgres serialization failures (40001) and deadlocks (40P01) are
+// retryable as well as SQLite SQLITE_BUSY and SQLITE_LOCKED errors.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		code := stateErr.SQLState()
+		return code == "40001" || code == "40P01"
+	}
+	return isRetryableSQLiteError(err)
+}
+
+func (p RetryPolicy) run(ctx context.Context, fn func() error) error {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	minDelay := p.MinDelay
+	if minDelay <= 0 {
+		minDelay = 10 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = time.Second
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+	delay := minDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxAttempts || !retryable(err) {
+			return err
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		// Use random delay in range [delay/2, delay].
+		jitter := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		timer := time.NewTimer(jitter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
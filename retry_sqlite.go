@@ -0,0 +1,19 @@
+//go:build cgo
+
+package gosql
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// isRetryableSQLiteError reports whether error is SQLITE_BUSY or
+// SQLITE_LOCKED error of github.com/mattn/go-sqlite3.
+func isRetryableSQLiteError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
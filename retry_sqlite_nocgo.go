@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package gosql
+
+// isRetryableSQLiteError always returns false since github.com/mattn/go-sqlite3
+// driver requires cgo.
+func isRetryableSQLiteError(err error) bool {
+	return false
+}
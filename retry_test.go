@@ -0,0 +1,144 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+type testSQLStateError string
+
+func (e testSQLStateError) Error() string {
+	return "sqlstate " + string(e)
+}
+
+func (e testSQLStateError) SQLState() string {
+	return string(e)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errors.New("some error"), false},
+		{testSQLStateError("40001"), true},
+		{testSQLStateError("40P01"), true},
+		{testSQLStateError("23505"), false},
+		{fmt.Errorf("wrapped: %w", testSQLStateError("40001")), true},
+		{errors.New("database is locked"), false},
+		{sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		{sqlite3.Error{Code: sqlite3.ErrLocked}, true},
+		{sqlite3.Error{Code: sqlite3.ErrConstraint}, false},
+		{fmt.Errorf("wrapped: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), true},
+		{errors.Join(errors.New("other"), sqlite3.Error{Code: sqlite3.ErrLocked}), true},
+		{sql.ErrNoRows, false},
+	}
+	for _, test := range tests {
+		if v := IsRetryableError(test.err); v != test.retryable {
+			t.Fatalf("Expected %v for %v, got %v", test.retryable, test.err, v)
+		}
+	}
+}
+
+func TestIsRetryableSQLiteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.sqlite")
+	open := func() *sql.DB {
+		db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=0")
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	}
+	db1, db2 := open(), open()
+	ctx := context.Background()
+	if _, err := db1.ExecContext(ctx, `CREATE TABLE "t1" ("id" INTEGER)`); err != nil {
+		t.Fatal("Error:", err)
+	}
+	tx, err := db1.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, `INSERT INTO "t1" VALUES (1)`); err != nil {
+		t.Fatal("Error:", err)
+	}
+	_, err = db2.ExecContext(ctx, `INSERT INTO "t1" VALUES (2)`)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if !IsRetryableError(err) {
+		t.Fatalf("Expected retryable error, got %v", err)
+	}
+}
+
+func TestWrapTxRetry(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	policy := RetryPolicy{MaxAttempts: 3, MinDelay: time.Millisecond}
+	t.Run("Success", func(t *testing.T) {
+		attempts := 0
+		if err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+			attempts++
+			if attempts < 3 {
+				return testSQLStateError("40001")
+			}
+			return nil
+		}, WithRetry(policy)); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+	t.Run("MaxAttempts", func(t *testing.T) {
+		attempts := 0
+		err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+			attempts++
+			return testSQLStateError("40001")
+		}, WithRetry(policy))
+		if !errors.Is(err, testSQLStateError("40001")) {
+			t.Fatal("Unexpected error:", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+	t.Run("NotRetryable", func(t *testing.T) {
+		attempts := 0
+		expectedErr := errors.New("test error")
+		err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+			attempts++
+			return expectedErr
+		}, WithRetry(policy))
+		if err != expectedErr {
+			t.Fatal("Unexpected error:", err)
+		}
+		if attempts != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		attempts := 0
+		err := WrapTx(ctx, db, func(tx *sql.Tx) error {
+			attempts++
+			cancel()
+			return testSQLStateError("40001")
+		}, WithRetry(RetryPolicy{MaxAttempts: 3, MinDelay: time.Hour}))
+		if !errors.Is(err, testSQLStateError("40001")) {
+			t.Fatal("Unexpected error:", err)
+		}
+		if attempts != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+}
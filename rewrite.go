@@ -0,0 +1,131 @@
+package gosql
+
+import (
+	"strings"
+)
+
+// queryWrapper represents query that wraps another query.
+type queryWrapper interface {
+	unwrap() Query
+	wrap(query Query) Query
+}
+
+// rewriteBuilder represents builder that rewrites queries before build.
+type rewriteBuilder struct {
+	Builder
+	rewrite func(Query) Query
+}
+
+func (b rewriteBuilder) Build(query Query) (string, []any) {
+	return b.Builder.Build(b.rewrite(query))
+}
+
+func (b rewriteBuilder) BuildString(query Query) string {
+	return b.Builder.BuildString(b.rewrite(query))
+}
+
+func (b rewriteBuilder) BuildInline(query Query) string {
+	return BuildInline(b.Builder, b.rewrite(query))
+}
+
+func andWhere(where BoolExpr, expr BoolExpr) BoolExpr {
+	if where == nil {
+		return expr
+	}
+	return where.And(expr)
+}
+
+// setInsertValue sets value of column for insert query, replacing
+// existing one.
+func setInsertValue(query InsertQuery, column string, value any) InsertQuery {
+	names, values, ok := setColumnValue(query.Names(), query.Values(), column, value)
+	if !ok {
+		return query
+	}
+	return query.SetNames(names...).SetValues(values...)
+}
+
+// setUpdateValue sets value of column for update query, replacing
+// existing one.
+func setUpdateValue(query UpdateQuery, column string, value any) UpdateQuery {
+	names, values, ok := setColumnValue(query.Names(), query.Values(), column, value)
+	if !ok {
+		return query
+	}
+	return query.SetNames(names...).SetValues(values...)
+}
+
+// replaceUpdateValue replaces value of column for update query when
+// column is assigned.
+func replaceUpdateValue(query UpdateQuery, column string, value any) UpdateQuery {
+	for _, name := range query.Names() {
+		if name == column {
+			return setUpdateValue(query, column, value)
+		}
+	}
+	return query
+}
+
+// setColumnValue returns names and values where value of column is
+// replaced or appended.
+//
+// It returns false when amounts of names and values differ.
+func setColumnValue(
+	names []string, values []Value, column string, value any,
+) ([]string, []any, bool) {
+	if len(names) != len(values) {
+		// Invalid query will panic on build.
+		return nil, nil, false
+	}
+	result := make([]any, len(values), len(values)+1)
+	for i, v := range values {
+		result[i] = v
+	}
+	for i, name := range names {
+		if name == column {
+			result[i] = value
+			return names, result, true
+		}
+	}
+	return append(names, column), append(result, value), true
+}
+
+// buildError represents error of query build.
+//
+// Builders panic with buildError, so Exec, QueryRows and helpers based
+// on them can return wrapped error instead of panic.
+type buildError struct {
+	err error
+}
+
+func (e buildError) Error() string {
+	return e.err.Error()
+}
+
+func (e buildError) Unwrap() error {
+	return e.err
+}
+
+// lookupTable returns value registered for table.
+//
+// Table in default schema of dialect ("public" for Postgres and "main"
+// for SQLite) matches registration without schema and vice versa.
+// Tables in other schemas should be registered with schema.
+func lookupTable(values map[string]string, dialect Dialect, table string) (string, bool) {
+	if value, ok := values[table]; ok {
+		return value, true
+	}
+	schema := "main."
+	if dialect == PostgresDialect {
+		schema = "public."
+	}
+	if strings.HasPrefix(table, schema) {
+		value, ok := values[strings.TrimPrefix(table, schema)]
+		return value, ok
+	}
+	if !strings.Contains(table, ".") {
+		value, ok := values[schema+table]
+		return value, ok
+	}
+	return "", false
+}
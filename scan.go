@@ -0,0 +1,182 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// ScanOption represents option for scanning of rows into structs.
+type ScanOption func(opts *scanOptions)
+
+type scanOptions struct {
+	strict bool
+}
+
+// WithStrictColumns represents option that requires each column of
+// result to be mapped to struct field and each struct field to be
+// present in result.
+func WithStrictColumns(strict bool) ScanOption {
+	return func(opts *scanOptions) {
+		opts.strict = strict
+	}
+}
+
+// SelectAll builds and executes select query and scans all rows into
+// slice of structs.
+//
+// Columns are mapped to struct fields using tags like `db:"name"`. When
+// query has no selected expressions, all mapped columns are selected.
+func SelectAll[T any](
+	ctx context.Context, r QueryRunner, query SelectQuery, options ...ScanOption,
+) ([]T, error) {
+	query, err := selectStructNames[T](query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := QueryRows(ctx, r, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	return ScanAll[T](rows, options...)
+}
+
+// SelectOne builds and executes select query and scans first row into
+// struct.
+//
+// If query returns no rows sql.ErrNoRows is returned.
+func SelectOne[T any](
+	ctx context.Context, r QueryRunner, query SelectQuery, options ...ScanOption,
+) (T, error) {
+	var empty T
+	query, err := selectStructNames[T](query)
+	if err != nil {
+		return empty, err
+	}
+	rows, err := QueryRows(ctx, r, query)
+	if err != nil {
+		return empty, err
+	}
+	defer func() { _ = rows.Close() }()
+	scanner, err := newRowScanner[T](rows, options)
+	if err != nil {
+		return empty, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return empty, err
+		}
+		return empty, sql.ErrNoRows
+	}
+	row, err := scanner.scan(rows)
+	if err != nil {
+		return empty, err
+	}
+	return row, rows.Close()
+}
+
+// ScanAll scans all rows into slice of structs.
+//
+// Rows are not closed, so caller is responsible for closing them.
+func ScanAll[T any](rows *sql.Rows, options ...ScanOption) ([]T, error) {
+	scanner, err := newRowScanner[T](rows, options)
+	if err != nil {
+		return nil, err
+	}
+	var result []T
+	for rows.Next() {
+		row, err := scanner.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// rowScanner represents scanner of rows into structs.
+type rowScanner[T any] struct {
+	// indexes contains index of struct field for each column or nil for
+	// columns that should be skipped.
+	indexes [][]int
+}
+
+func newRowScanner[T any](rows *sql.Rows, options []ScanOption) (*rowScanner[T], error) {
+	var opts scanOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	typ, err := structTypeOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	fields := getStructFields(typ)
+	scanner := rowScanner[T]{indexes: make([][]int, len(columns))}
+	used := map[string]struct{}{}
+	for i, column := range columns {
+		field, ok := fields.fieldByName(column)
+		if !ok {
+			if opts.strict {
+				return nil, fmt.Errorf("column %q is not mapped to %v", column, typ)
+			}
+			continue
+		}
+		scanner.indexes[i] = field.index
+		used[column] = struct{}{}
+	}
+	if opts.strict {
+		for _, field := range fields.list {
+			if _, ok := used[field.name]; !ok {
+				return nil, fmt.Errorf("column %q of %v is missing", field.name, typ)
+			}
+		}
+	}
+	return &scanner, nil
+}
+
+func (s *rowScanner[T]) scan(rows *sql.Rows) (T, error) {
+	var row T
+	value := reflect.ValueOf(&row).Elem()
+	dest := make([]any, len(s.indexes))
+	for i, index := range s.indexes {
+		if index == nil {
+			dest[i] = new(any)
+			continue
+		}
+		dest[i] = fieldByIndex(value, index).Addr().Interface()
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return row, err
+	}
+	return row, nil
+}
+
+// selectStructNames returns query that selects all mapped columns when
+// query has no selected expressions.
+func selectStructNames[T any](query SelectQuery) (SelectQuery, error) {
+	if len(query.Exprs()) > 0 {
+		return query, nil
+	}
+	typ, err := structTypeOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	return query.Clone().SetNames(getStructFields(typ).names()...), nil
+}
+
+func structTypeOf[T any]() (reflect.Type, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct but got %v", typ)
+	}
+	return typ, nil
+}
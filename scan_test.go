@@ -0,0 +1,122 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testScanBase struct {
+	ID int64 `db:"id"`
+}
+
+type TestScanExtra struct {
+	Note string `db:"note"`
+}
+
+type testScanRow struct {
+	testScanBase
+	*TestScanExtra
+	Name    string         `db:"name"`
+	Age     *int           `db:"age"`
+	Email   sql.NullString `db:"email"`
+	Skipped string         `db:"-"`
+	Ignored string
+}
+
+func testPrepareScanTable(tb testing.TB, db *DB) {
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "t1" ("id" INTEGER PRIMARY KEY, "name" TEXT, "age" INTEGER, "email" TEXT, "note" TEXT, "extra" TEXT)`,
+	); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	if _, err := db.ExecContext(
+		ctx, `INSERT INTO "t1" VALUES (1, 'a', 10, 'a@a', 'n1', 'x'), (2, 'b', NULL, NULL, 'n2', 'y')`,
+	); err != nil {
+		tb.Fatal("Error:", err)
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	testPrepareScanTable(t, db)
+	ctx := context.Background()
+	rows, err := SelectAll[testScanRow](ctx, db, db.Select("t1").SetOrderBy("id"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	age := 10
+	expected := []testScanRow{
+		{
+			testScanBase:  testScanBase{ID: 1},
+			TestScanExtra: &TestScanExtra{Note: "n1"},
+			Name:          "a",
+			Age:           &age,
+			Email:         sql.NullString{String: "a@a", Valid: true},
+		},
+		{
+			testScanBase:  testScanBase{ID: 2},
+			TestScanExtra: &TestScanExtra{Note: "n2"},
+			Name:          "b",
+		},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, rows)
+	}
+	if _, err := SelectAll[testScanRow](
+		ctx, db, db.Select("t1"), WithStrictColumns(true),
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := SelectAll[testScanRow](
+		ctx, db, db.Select("t1").SetNames("id", "name", "extra"),
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := SelectAll[testScanRow](
+		ctx, db, db.Select("t1").SetNames("id", "name", "age", "email", "note", "extra"), WithStrictColumns(true),
+	); err == nil {
+		t.Fatal("Expected error for unknown column")
+	}
+	if _, err := SelectAll[testScanRow](
+		ctx, db, db.Select("t1").SetNames("id", "name"), WithStrictColumns(true),
+	); err == nil {
+		t.Fatal("Expected error for missing column")
+	}
+	if _, err := SelectAll[int](ctx, db, db.Select("t1")); err == nil {
+		t.Fatal("Expected error for non-struct type")
+	}
+}
+
+func TestSelectOne(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	testPrepareScanTable(t, db)
+	ctx := context.Background()
+	row, err := SelectOne[testScanRow](ctx, db, db.Select("t1").SetWhere(Column("id").Equal(2)))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if row.ID != 2 || row.Name != "b" || row.Age != nil || row.Email.Valid {
+		t.Fatalf("Unexpected row: %+v", row)
+	}
+	if _, err := SelectOne[testScanRow](
+		ctx, db, db.Select("t1").SetWhere(Column("id").Equal(3)),
+	); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+}
+
+func TestBindStruct(t *testing.T) {
+	b := NewBuilder(SQLiteDialect)
+	_, values := b.Build(b.Select("t1").SetWhere(Column("id").Equal(Param("id")).And(Column("note").Equal(Param("note")))))
+	v1, err := Bind(values, testScanRow{testScanBase: testScanBase{ID: 5}})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if expected := []any{int64(5), nil}; !reflect.DeepEqual(v1, expected) {
+		t.Fatalf("Expected %v, got %v", expected, v1)
+	}
+}
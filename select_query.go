@@ -5,51 +5,124 @@ import (
 )
 
 // SelectQuery represents SQL select query.
+//
+// Setters do not modify query and return its modified copy, so shared
+// query can be safely extended.
 type SelectQuery interface {
 	Query
-	SetNames(names ...string)
-	SetWhere(where BoolExpr)
-	SetOrderBy(names ...any)
-	SetLimit(limit int)
+	SetNames(names ...string) SelectQuery
+	SetExprs(exprs ...any) SelectQuery
+	SetWhere(where BoolExpr) SelectQuery
+	SetOrderBy(names ...any) SelectQuery
+	SetLimit(limit int) SelectQuery
+	// Clone returns deep copy of query.
+	Clone() SelectQuery
+	// Table returns name of table.
+	Table() string
+	// Exprs returns list of selected expressions.
+	Exprs() []Expr
+	// Where returns condition of query.
+	Where() BoolExpr
+	// OrderBy returns list of sorting expressions.
+	OrderBy() []OrderExpr
+	// Limit returns limit of query.
+	Limit() int
 }
 
 type selectQuery struct {
 	table   string
-	names   []string
+	names   []Expr
 	where   BoolExpr
 	orderBy []OrderExpr
 	limit   int
+	// dialect contains dialect of builder that created query.
+	dialect queryDialect
 }
 
-func (q *selectQuery) SetNames(names ...string) {
-	q.names = names
+func (q *selectQuery) SetNames(names ...string) SelectQuery {
+	clone := *q
+	clone.names = nil
+	for _, name := range names {
+		clone.names = append(clone.names, Column(name))
+	}
+	return &clone
+}
+
+// SetExprs sets list of selected expressions.
+//
+// Strings are treated as column names.
+func (q *selectQuery) SetExprs(exprs ...any) SelectQuery {
+	clone := *q
+	clone.names = nil
+	for _, expr := range exprs {
+		clone.names = append(clone.names, wrapExpression(expr))
+	}
+	return &clone
 }
 
-func (q *selectQuery) SetWhere(where BoolExpr) {
-	q.where = where
+func (q *selectQuery) SetWhere(where BoolExpr) SelectQuery {
+	clone := *q
+	clone.where = where
+	return &clone
 }
 
-func (q *selectQuery) SetOrderBy(names ...any) {
-	q.orderBy = nil
+func (q *selectQuery) SetOrderBy(names ...any) SelectQuery {
+	clone := *q
+	clone.orderBy = nil
 	for _, name := range names {
-		q.orderBy = append(q.orderBy, wrapOrderExpression(name))
+		clone.orderBy = append(clone.orderBy, wrapOrderExpression(name))
 	}
+	return &clone
 }
 
-func (q *selectQuery) SetLimit(limit int) {
-	q.limit = limit
+func (q *selectQuery) SetLimit(limit int) SelectQuery {
+	clone := *q
+	clone.limit = limit
+	return &clone
+}
+
+func (q *selectQuery) Clone() SelectQuery {
+	clone := *q
+	clone.names = append([]Expr(nil), q.names...)
+	clone.orderBy = append([]OrderExpr(nil), q.orderBy...)
+	return &clone
+}
+
+func (q selectQuery) Table() string {
+	return q.table
+}
+
+func (q selectQuery) Exprs() []Expr {
+	return append([]Expr(nil), q.names...)
+}
+
+func (q selectQuery) Where() BoolExpr {
+	return q.where
+}
+
+func (q selectQuery) OrderBy() []OrderExpr {
+	return append([]OrderExpr(nil), q.orderBy...)
+}
+
+func (q selectQuery) Limit() int {
+	return q.limit
 }
 
 func (q selectQuery) WriteQuery(w Writer) {
+	w = q.dialect.writer(w)
 	w.WriteString("SELECT ")
 	q.writeNames(w)
-	w.WriteString(" FROM ")
+	writeClause(w, "FROM")
 	w.WriteName(q.table)
 	q.writeWhere(w)
 	q.writeOrderBy(w)
 	if q.limit > 0 {
-		w.WriteString(" LIMIT ")
-		w.WriteString(strconv.Itoa(q.limit))
+		writeClause(w, "LIMIT")
+		if wr, ok := w.(*writer); ok && wr.fingerprint {
+			w.WriteRune('?')
+		} else {
+			w.WriteString(strconv.Itoa(q.limit))
+		}
 	}
 }
 
@@ -62,12 +135,12 @@ func (q selectQuery) writeNames(w Writer) {
 		if i > 0 {
 			w.WriteString(", ")
 		}
-		w.WriteName(name)
+		name.WriteExpr(w)
 	}
 }
 
 func (q selectQuery) writeWhere(w Writer) {
-	w.WriteString(" WHERE ")
+	writeClause(w, "WHERE")
 	if q.where == nil {
 		w.WriteString("1 = 1")
 		return
@@ -79,7 +152,7 @@ func (q selectQuery) writeOrderBy(w Writer) {
 	if len(q.orderBy) == 0 {
 		return
 	}
-	w.WriteString(" ORDER BY ")
+	writeClause(w, "ORDER BY")
 	for i, name := range q.orderBy {
 		if i > 0 {
 			w.WriteString(", ")
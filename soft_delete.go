@@ -0,0 +1,153 @@
+package gosql
+
+// SoftDeleteBuilder represents builder that implements soft deletion
+// for registered tables.
+//
+// For registered tables delete queries are rendered as
+// "UPDATE table SET column = CURRENT_TIMESTAMP" and predicate
+// "column IS NULL" is added to select and update queries. Use WithDeleted
+// or SelectWithDeleted and HardDelete to disable this behavior for
+// specific queries.
+type SoftDeleteBuilder struct {
+	Builder
+	columns map[string]string
+}
+
+// NewSoftDeleteBuilder creates a new instance of SoftDeleteBuilder.
+//
+// Columns contains mapping from table name to name of column that
+// contains deletion time. Tables are matched like in NewTenantBuilder.
+func NewSoftDeleteBuilder(b Builder, columns map[string]string) *SoftDeleteBuilder {
+	return &SoftDeleteBuilder{Builder: b, columns: columns}
+}
+
+func (b *SoftDeleteBuilder) Build(query Query) (string, []any) {
+	return b.Builder.Build(b.rewrite(query))
+}
+
+func (b *SoftDeleteBuilder) BuildString(query Query) string {
+	return b.Builder.BuildString(b.rewrite(query))
+}
+
+func (b *SoftDeleteBuilder) BuildInline(query Query) string {
+	return BuildInline(b.Builder, b.rewrite(query))
+}
+
+func (b *SoftDeleteBuilder) rewrite(query Query) Query {
+	switch q := query.(type) {
+	case withDeleted:
+		return q.query
+	case withDeletedSelect:
+		return q.SelectQuery
+	case hardDelete:
+		return q.query
+	case SelectQuery:
+		if column, ok := lookupTable(b.columns, b.Dialect(), q.Table()); ok {
+			return q.Clone().SetWhere(andWhere(q.Where(), Column(column).Equal(nil)))
+		}
+	case UpdateQuery:
+		if column, ok := lookupTable(b.columns, b.Dialect(), q.Table()); ok {
+			return q.Clone().SetWhere(andWhere(q.Where(), Column(column).Equal(nil)))
+		}
+	case DeleteQuery:
+		if column, ok := lookupTable(b.columns, b.Dialect(), q.Table()); ok {
+			return b.Builder.Update(q.Table()).
+				SetNames(column).
+				SetValues(Raw("CURRENT_TIMESTAMP")).
+				SetWhere(andWhere(q.Where(), Column(column).Equal(nil)))
+		}
+	}
+	return query
+}
+
+type withDeleted struct {
+	query Query
+}
+
+// WithDeleted returns query that is not affected by SoftDeleteBuilder,
+// so soft-deleted rows are not excluded.
+func WithDeleted(query Query) Query {
+	return withDeleted{query: query}
+}
+
+func (q withDeleted) WriteQuery(w Writer) {
+	q.query.WriteQuery(w)
+}
+
+func (q withDeleted) unwrap() Query {
+	return q.query
+}
+
+func (q withDeleted) wrap(query Query) Query {
+	return withDeleted{query: query}
+}
+
+type withDeletedSelect struct {
+	SelectQuery
+}
+
+// SelectWithDeleted returns select query that is not affected by
+// SoftDeleteBuilder, so soft-deleted rows are not excluded.
+//
+// Unlike WithDeleted, returned query can be modified and passed to
+// SelectAll, SelectOne, Iterate and NewCursor.
+func SelectWithDeleted(query SelectQuery) SelectQuery {
+	return withDeletedSelect{SelectQuery: query}
+}
+
+func (q withDeletedSelect) SetNames(names ...string) SelectQuery {
+	return withDeletedSelect{SelectQuery: q.SelectQuery.SetNames(names...)}
+}
+
+func (q withDeletedSelect) SetExprs(exprs ...any) SelectQuery {
+	return withDeletedSelect{SelectQuery: q.SelectQuery.SetExprs(exprs...)}
+}
+
+func (q withDeletedSelect) SetWhere(where BoolExpr) SelectQuery {
+	return withDeletedSelect{SelectQuery: q.SelectQuery.SetWhere(where)}
+}
+
+func (q withDeletedSelect) SetOrderBy(names ...any) SelectQuery {
+	return withDeletedSelect{SelectQuery: q.SelectQuery.SetOrderBy(names...)}
+}
+
+func (q withDeletedSelect) SetLimit(limit int) SelectQuery {
+	return withDeletedSelect{SelectQuery: q.SelectQuery.SetLimit(limit)}
+}
+
+func (q withDeletedSelect) Clone() SelectQuery {
+	return withDeletedSelect{SelectQuery: q.SelectQuery.Clone()}
+}
+
+func (q withDeletedSelect) unwrap() Query {
+	return q.SelectQuery
+}
+
+func (q withDeletedSelect) wrap(query Query) Query {
+	if selectQuery, ok := query.(SelectQuery); ok {
+		return withDeletedSelect{SelectQuery: selectQuery}
+	}
+	return withDeleted{query: query}
+}
+
+type hardDelete struct {
+	query Query
+}
+
+// HardDelete returns delete query that is not affected by
+// SoftDeleteBuilder, so rows are physically deleted.
+func HardDelete(query DeleteQuery) Query {
+	return hardDelete{query: query}
+}
+
+func (q hardDelete) WriteQuery(w Writer) {
+	q.query.WriteQuery(w)
+}
+
+func (q hardDelete) unwrap() Query {
+	return q.query
+}
+
+func (q hardDelete) wrap(query Query) Query {
+	return hardDelete{query: query}
+}
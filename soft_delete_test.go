@@ -0,0 +1,120 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestSoftDeleteBuilder(t *testing.T) {
+	b := NewSoftDeleteBuilder(NewBuilder(SQLiteDialect), map[string]string{
+		"users": "deleted_at",
+	})
+	inputs := []Query{
+		b.Select("users").SetWhere(Column("id").Equal(1)),
+		b.Update("users").SetNames("name").SetValues("test"),
+		b.Delete("users").SetWhere(Column("id").Equal(1)),
+		b.Delete("users"),
+		WithDeleted(b.Select("users")),
+		HardDelete(b.Delete("users").SetWhere(Column("id").Equal(1))),
+		b.Insert("users").SetNames("name").SetValues("test"),
+		b.Delete("groups"),
+		b.Select("main.users"),
+		b.Select("other.users"),
+		SelectWithDeleted(b.Select("users")).SetWhere(Column("id").Equal(1)),
+		b.Delete("main.users"),
+	}
+	outputs := []string{
+		`SELECT * FROM "users" WHERE "id" = $1 AND "deleted_at" IS NULL`,
+		`UPDATE "users" SET "name" = $1 WHERE "deleted_at" IS NULL`,
+		`UPDATE "users" SET "deleted_at" = CURRENT_TIMESTAMP WHERE "id" = $1 AND "deleted_at" IS NULL`,
+		`UPDATE "users" SET "deleted_at" = CURRENT_TIMESTAMP WHERE "deleted_at" IS NULL`,
+		`SELECT * FROM "users" WHERE 1 = 1`,
+		`DELETE FROM "users" WHERE "id" = $1`,
+		`INSERT INTO "users" ("name") VALUES ($1)`,
+		`DELETE FROM "groups" WHERE 1 = 1`,
+		`SELECT * FROM "main"."users" WHERE "deleted_at" IS NULL`,
+		`SELECT * FROM "other"."users" WHERE 1 = 1`,
+		`SELECT * FROM "users" WHERE "id" = $1`,
+		`UPDATE "main"."users" SET "deleted_at" = CURRENT_TIMESTAMP WHERE "deleted_at" IS NULL`,
+	}
+	for i, input := range inputs {
+		if query := b.BuildString(input); query != outputs[i] {
+			t.Errorf("Expected %q, got %q", outputs[i], query)
+		}
+	}
+	s1 := `/* not for execution */ SELECT * FROM "users" WHERE "deleted_at" IS NULL`
+	if s := b.BuildInline(b.Select("users")); s != s1 {
+		t.Fatalf("Expected %q, got %q", s1, s)
+	}
+}
+
+func TestSoftDeleteTenantBuilder(t *testing.T) {
+	columns := map[string]string{"users": "tenant_id"}
+	ctx := WithTenant(context.Background(), 42)
+	inner := NewTenantBuilder(NewBuilder(SQLiteDialect), columns).WithContext(ctx)
+	b1 := NewSoftDeleteBuilder(inner, map[string]string{"users": "deleted_at"})
+	s1 := `UPDATE "users" SET "deleted_at" = CURRENT_TIMESTAMP WHERE "id" = $1 AND "deleted_at" IS NULL AND "tenant_id" = $2`
+	if s := b1.BuildString(b1.Delete("users").SetWhere(Column("id").Equal(1))); s != s1 {
+		t.Fatalf("Expected %q, got %q", s1, s)
+	}
+	outer := NewSoftDeleteBuilder(NewBuilder(SQLiteDialect), map[string]string{"users": "deleted_at"})
+	b2 := NewTenantBuilder(outer, columns).WithContext(ctx)
+	s2 := `SELECT * FROM "users" WHERE "tenant_id" = $1`
+	if s := b2.BuildString(WithDeleted(b2.Select("users"))); s != s2 {
+		t.Fatalf("Expected %q, got %q", s2, s)
+	}
+	s3 := `DELETE FROM "users" WHERE "tenant_id" = $1`
+	if s := b2.BuildString(HardDelete(b2.Delete("users"))); s != s3 {
+		t.Fatalf("Expected %q, got %q", s3, s)
+	}
+	s4 := `SELECT * FROM "users" WHERE "id" = $1 AND "tenant_id" = $2`
+	if s := b2.BuildString(SelectWithDeleted(b2.Select("users")).SetWhere(Column("id").Equal(1))); s != s4 {
+		t.Fatalf("Expected %q, got %q", s4, s)
+	}
+}
+
+func TestSoftDeleteStore(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "users" ("id" INTEGER PRIMARY KEY, "name" TEXT, "age" INTEGER, "deleted_at" TIMESTAMP)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	db.Builder = NewSoftDeleteBuilder(db.Builder, map[string]string{"users": "deleted_at"})
+	store := NewStore[testStoreRow]("users", "id")
+	for _, name := range []string{"a", "b"} {
+		if err := store.Create(ctx, db, &testStoreRow{Name: name}); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := store.Delete(ctx, db, 1); err != nil {
+		t.Fatal("Error:", err)
+	}
+	rows, err := store.FindWhere(ctx, db, nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(rows) != 1 || rows[0].ID != 2 {
+		t.Fatalf("Unexpected rows: %+v", rows)
+	}
+	rows, err = SelectAll[testStoreRow](ctx, db, SelectWithDeleted(store.Select()).SetOrderBy("id"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(rows) != 2 || rows[0].ID != 1 || rows[1].ID != 2 {
+		t.Fatalf("Unexpected rows: %+v", rows)
+	}
+	row, err := SelectOne[testStoreRow](ctx, db, SelectWithDeleted(db.Select("main.users")).SetWhere(Column("id").Equal(1)))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if row.Name != "a" {
+		t.Fatalf("Unexpected row: %+v", row)
+	}
+	if _, err := SelectOne[testStoreRow](ctx, db, db.Select("main.users").SetWhere(Column("id").Equal(1))); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+}
@@ -0,0 +1,173 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Store represents typed repository of rows stored in table.
+//
+// Rows are mapped to struct fields using tags like `db:"name"`. When
+// primary key field is marked as readonly or autoincrement, its value
+// is retrieved from database after insertion.
+type Store[T any] struct {
+	table      string
+	primaryKey structField
+}
+
+// NewStore creates a new instance of Store for specified table with
+// primary key column.
+func NewStore[T any](table, primaryKey string) *Store[T] {
+	typ, err := structTypeOf[T]()
+	if err != nil {
+		panic(err)
+	}
+	field, ok := getStructFields(typ).fieldByName(primaryKey)
+	if !ok {
+		panic(fmt.Errorf("column %q is not mapped to %v", primaryKey, typ))
+	}
+	return &Store[T]{table: table, primaryKey: field}
+}
+
+// Create inserts row and sets its primary key.
+//
+// When primary key is retrieved from database, query uses RETURNING
+// clause that is supported by both SQLite and Postgres.
+func (s *Store[T]) Create(ctx context.Context, r QueryRunner, row *T) error {
+	query := s.createQuery(row)
+	if !s.primaryKey.readOnly {
+		_, err := Exec(ctx, r, query)
+		return err
+	}
+	id := fieldByIndex(reflect.ValueOf(row).Elem(), s.primaryKey.index)
+	rows, err := QueryRows(ctx, r, query)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rows.Scan(id.Addr().Interface()); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+func (s *Store[T]) createQuery(row *T) Query {
+	query := (&insertQuery{table: s.table}).SetRow(row)
+	if !s.primaryKey.readOnly {
+		return query
+	}
+	return insertReturning{query: query, names: []string{s.primaryKey.name}}
+}
+
+// Get returns row by primary key.
+//
+// If there is no such row sql.ErrNoRows is returned.
+func (s *Store[T]) Get(ctx context.Context, r QueryRunner, id any) (T, error) {
+	return SelectOne[T](ctx, r, s.Select().SetWhere(s.wherePrimaryKey(id)))
+}
+
+// Update updates row by its primary key.
+//
+// When columns are specified, only these columns are updated. If there
+// is no such row sql.ErrNoRows is returned.
+func (s *Store[T]) Update(ctx context.Context, r QueryRunner, row *T, columns ...string) error {
+	var id any
+	if v, ok := lookupFieldByIndex(reflect.ValueOf(row).Elem(), s.primaryKey.index); ok {
+		id = v.Interface()
+	}
+	query := (&updateQuery{table: s.table}).
+		SetRow(row, columns...).
+		SetWhere(s.wherePrimaryKey(id))
+	return expectAffected(Exec(ctx, r, query))
+}
+
+// Delete deletes row by primary key.
+//
+// If there is no such row sql.ErrNoRows is returned.
+func (s *Store[T]) Delete(ctx context.Context, r QueryRunner, id any) error {
+	query := (&deleteQuery{table: s.table}).SetWhere(s.wherePrimaryKey(id))
+	return expectAffected(Exec(ctx, r, query))
+}
+
+// FindWhere returns all rows that match condition ordered by primary key.
+func (s *Store[T]) FindWhere(ctx context.Context, r QueryRunner, where BoolExpr) ([]T, error) {
+	query := s.Select().SetWhere(where).SetOrderBy(s.primaryKey.name)
+	return SelectAll[T](ctx, r, query)
+}
+
+// FindPage returns at most limit rows that match condition and have
+// primary key greater than after, ordered by primary key.
+//
+// Use nil after to get first page and primary key of last row as after
+// to get next one.
+func (s *Store[T]) FindPage(
+	ctx context.Context, r QueryRunner, where BoolExpr, after any, limit int,
+) ([]T, error) {
+	if after != nil {
+		where = andWhere(where, Column(s.primaryKey.name).Greater(after))
+	}
+	query := s.Select().
+		SetWhere(where).
+		SetOrderBy(s.primaryKey.name).
+		SetLimit(limit)
+	return SelectAll[T](ctx, r, query)
+}
+
+// Select returns select query for table of store.
+//
+// Query can be extended and passed to SelectAll, SelectOne or Iterate,
+// for example with SelectWithDeleted.
+func (s *Store[T]) Select() SelectQuery {
+	return &selectQuery{table: s.table}
+}
+
+func (s *Store[T]) wherePrimaryKey(id any) BoolExpr {
+	return Column(s.primaryKey.name).Equal(id)
+}
+
+// insertReturning represents insert query with RETURNING clause.
+type insertReturning struct {
+	query Query
+	names []string
+}
+
+func (q insertReturning) WriteQuery(w Writer) {
+	q.query.WriteQuery(w)
+	writeClause(w, "RETURNING")
+	for i, name := range q.names {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteName(name)
+	}
+}
+
+func (q insertReturning) unwrap() Query {
+	return q.query
+}
+
+func (q insertReturning) wrap(query Query) Query {
+	return insertReturning{query: query, names: q.names}
+}
+
+func expectAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
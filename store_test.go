@@ -0,0 +1,130 @@
+package gosql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+type testStoreRow struct {
+	ID   int64  `db:"id,autoincrement"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+func TestStore(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "users" ("id" INTEGER PRIMARY KEY, "name" TEXT, "age" INTEGER)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	store := NewStore[testStoreRow]("users", "id")
+	for i, name := range []string{"a", "b", "c", "d"} {
+		row := testStoreRow{Name: name, Age: 10 + i}
+		if err := store.Create(ctx, db, &row); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if row.ID != int64(i+1) {
+			t.Fatalf("Expected id %d, got %d", i+1, row.ID)
+		}
+	}
+	row, err := store.Get(ctx, db, 2)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if row != (testStoreRow{ID: 2, Name: "b", Age: 11}) {
+		t.Fatalf("Unexpected row: %+v", row)
+	}
+	row.Name, row.Age = "bb", 20
+	if err := store.Update(ctx, db, &row, "name"); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if row, err := store.Get(ctx, db, 2); err != nil {
+		t.Fatal("Error:", err)
+	} else if row != (testStoreRow{ID: 2, Name: "bb", Age: 11}) {
+		t.Fatalf("Unexpected row: %+v", row)
+	}
+	if err := store.Delete(ctx, db, 3); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := store.Get(ctx, db, 3); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	if err := store.Delete(ctx, db, 3); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	if err := store.Update(ctx, db, &testStoreRow{ID: 3}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	rows, err := store.FindWhere(ctx, db, Column("age").GreaterEqual(11))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(rows) != 2 || rows[0].ID != 2 || rows[1].ID != 4 {
+		t.Fatalf("Unexpected rows: %+v", rows)
+	}
+	page, err := store.FindPage(ctx, db, nil, nil, 2)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(page) != 2 || page[0].ID != 1 || page[1].ID != 2 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	page, err = store.FindPage(ctx, db, nil, page[1].ID, 2)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(page) != 1 || page[0].ID != 4 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	testExpectPanic(t, func() {
+		NewStore[testStoreRow]("users", "unknown")
+	})
+}
+
+type testStoreTextRow struct {
+	ID   string `db:"id,readonly"`
+	Name string `db:"name"`
+}
+
+func TestStoreReturning(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "items" ("id" TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(8)))), "name" TEXT)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	store := NewStore[testStoreTextRow]("items", "id")
+	row := testStoreTextRow{Name: "a"}
+	if err := store.Create(ctx, db, &row); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if row.ID == "" {
+		t.Fatal("Expected non-empty id")
+	}
+	if v, err := store.Get(ctx, db, row.ID); err != nil {
+		t.Fatal("Error:", err)
+	} else if v != row {
+		t.Fatalf("Expected %+v, got %+v", row, v)
+	}
+	tests := []struct {
+		builder Builder
+		query   string
+	}{
+		{NewBuilder(SQLiteDialect), `INSERT INTO "items" ("name") VALUES ($1) RETURNING "id"`},
+		{NewBuilder(PostgresDialect), `INSERT INTO "items" ("name") VALUES ($1) RETURNING "id"`},
+	}
+	for _, test := range tests {
+		query, values := test.builder.Build(store.createQuery(&row))
+		if query != test.query {
+			t.Fatalf("Expected %q, got %q", test.query, query)
+		}
+		if len(values) != 1 || values[0] != "a" {
+			t.Fatalf("Unexpected values: %v", values)
+		}
+	}
+}
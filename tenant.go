@@ -0,0 +1,107 @@
+package gosql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoTenant represents error when query touches table that requires
+// tenant, but tenant is not specified.
+var ErrNoTenant = errors.New("tenant is not specified")
+
+type tenantKey struct{}
+
+// WithTenant returns context with specified tenant.
+func WithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns tenant from context.
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+// TenantBuilder represents builder that injects tenant predicates into
+// queries for registered tables.
+//
+// For select, update and delete queries predicate "column = tenant" is
+// added to condition and for insert queries tenant column is filled.
+// Assignments of tenant column in update queries are replaced by tenant,
+// so rows cannot be moved to another tenant.
+//
+// Build panics with error that wraps ErrNoTenant when registered table is
+// touched without tenant, so TenantBuilder should be bound to tenant using
+// WithContext. Exec, QueryRows and helpers based on them return this
+// error instead of panic.
+type TenantBuilder struct {
+	Builder
+	columns map[string]string
+}
+
+// NewTenantBuilder creates a new instance of TenantBuilder.
+//
+// Columns contains mapping from table name to name of tenant column.
+// Tables in default schema like "public.users" for Postgres are matched
+// by their name without schema, tables in other schemas should be
+// registered with schema.
+func NewTenantBuilder(b Builder, columns map[string]string) *TenantBuilder {
+	return &TenantBuilder{Builder: b, columns: columns}
+}
+
+// WithContext returns builder bound to tenant from context.
+func (b *TenantBuilder) WithContext(ctx context.Context) Builder {
+	tenant, ok := TenantFromContext(ctx)
+	return rewriteBuilder{
+		Builder: b.Builder,
+		rewrite: func(query Query) Query {
+			return b.inject(query, tenant, ok)
+		},
+	}
+}
+
+func (b *TenantBuilder) Build(query Query) (string, []any) {
+	return b.Builder.Build(b.inject(query, nil, false))
+}
+
+func (b *TenantBuilder) BuildString(query Query) string {
+	return b.Builder.BuildString(b.inject(query, nil, false))
+}
+
+func (b *TenantBuilder) BuildInline(query Query) string {
+	return BuildInline(b.Builder, b.inject(query, nil, false))
+}
+
+func (b *TenantBuilder) column(table string, hasTenant bool) (string, bool) {
+	column, ok := lookupTable(b.columns, b.Dialect(), table)
+	if ok && !hasTenant {
+		panic(buildError{fmt.Errorf("%w for table %q", ErrNoTenant, table)})
+	}
+	return column, ok
+}
+
+func (b *TenantBuilder) inject(query Query, tenant any, hasTenant bool) Query {
+	switch q := query.(type) {
+	case queryWrapper:
+		return q.wrap(b.inject(q.unwrap(), tenant, hasTenant))
+	case SelectQuery:
+		if column, ok := b.column(q.Table(), hasTenant); ok {
+			return q.Clone().SetWhere(andWhere(q.Where(), Column(column).Equal(tenant)))
+		}
+	case UpdateQuery:
+		if column, ok := b.column(q.Table(), hasTenant); ok {
+			return replaceUpdateValue(q.Clone(), column, tenant).
+				SetWhere(andWhere(q.Where(), Column(column).Equal(tenant)))
+		}
+	case DeleteQuery:
+		if column, ok := b.column(q.Table(), hasTenant); ok {
+			return q.Clone().SetWhere(andWhere(q.Where(), Column(column).Equal(tenant)))
+		}
+	case InsertQuery:
+		if column, ok := b.column(q.Table(), hasTenant); ok {
+			return setInsertValue(q.Clone(), column, tenant)
+		}
+	}
+	return query
+}
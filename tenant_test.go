@@ -0,0 +1,112 @@
+package gosql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTenantBuilder(t *testing.T) {
+	tb := NewTenantBuilder(NewBuilder(PostgresDialect), map[string]string{
+		"users":        "tenant_id",
+		"other.groups": "tenant_id",
+	})
+	b := tb.WithContext(WithTenant(context.Background(), int64(42)))
+	inputs := []Query{
+		b.Select("users").SetWhere(Column("id").Equal(1).Or(Column("id").Equal(2))),
+		b.Select("users"),
+		b.Update("users").SetNames("name").SetValues("test"),
+		b.Delete("users").SetWhere(Column("id").Equal(1)),
+		b.Insert("users").SetNames("name").SetValues("test"),
+		b.Insert("users").SetNames("tenant_id", "name").SetValues(1, "test"),
+		b.Select("groups"),
+		b.Update("users").SetNames("tenant_id", "name").SetValues(1, "test"),
+		b.Select("public.users"),
+		b.Select("other.users"),
+		b.Select("other.groups"),
+		b.Select("public.groups"),
+	}
+	outputs := []string{
+		`SELECT * FROM "users" WHERE ("id" = $1 OR "id" = $2) AND "tenant_id" = $3`,
+		`SELECT * FROM "users" WHERE "tenant_id" = $1`,
+		`UPDATE "users" SET "name" = $1 WHERE "tenant_id" = $2`,
+		`DELETE FROM "users" WHERE "id" = $1 AND "tenant_id" = $2`,
+		`INSERT INTO "users" ("name", "tenant_id") VALUES ($1, $2)`,
+		`INSERT INTO "users" ("tenant_id", "name") VALUES ($1, $2)`,
+		`SELECT * FROM "groups" WHERE 1 = 1`,
+		`UPDATE "users" SET "tenant_id" = $1, "name" = $2 WHERE "tenant_id" = $3`,
+		`SELECT * FROM "public"."users" WHERE "tenant_id" = $1`,
+		`SELECT * FROM "other"."users" WHERE 1 = 1`,
+		`SELECT * FROM "other"."groups" WHERE "tenant_id" = $1`,
+		`SELECT * FROM "public"."groups" WHERE 1 = 1`,
+	}
+	for i, input := range inputs {
+		if query := b.BuildString(input); query != outputs[i] {
+			t.Errorf("Expected %q, got %q", outputs[i], query)
+		}
+	}
+	if _, v := b.Build(inputs[5]); !reflect.DeepEqual(v, []any{int64(42), "test"}) {
+		t.Fatalf("Unexpected values: %v", v)
+	}
+	if _, v := b.Build(inputs[7]); !reflect.DeepEqual(v, []any{int64(42), "test", int64(42)}) {
+		t.Fatalf("Unexpected values: %v", v)
+	}
+	if s := b.BuildString(inputs[1]); s != outputs[1] {
+		t.Fatalf("Query should not be changed: %q", s)
+	}
+	if s := tb.BuildString(tb.Select("groups")); s != outputs[6] {
+		t.Fatalf("Expected %q, got %q", outputs[6], s)
+	}
+	for _, fn := range []func(){
+		func() { tb.Build(tb.Select("users")) },
+		func() { tb.BuildString(tb.Delete("users")) },
+		func() { tb.BuildInline(tb.Update("users").SetNames("name").SetValues("test")) },
+		func() { tb.WithContext(context.Background()).Build(tb.Insert("users")) },
+	} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				if !ok || !errors.Is(err, ErrNoTenant) {
+					t.Fatalf("Expected %v panic, got %v", ErrNoTenant, err)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestTenantBuilderExec(t *testing.T) {
+	db := testNewSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(
+		ctx, `CREATE TABLE "users" ("id" INTEGER PRIMARY KEY, "tenant_id" INTEGER, "name" TEXT)`,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	db.Builder = NewTenantBuilder(db.Builder, map[string]string{
+		"main.users": "tenant_id",
+	})
+	if _, err := Exec(ctx, db, db.Insert("users").SetNames("name").SetValues("test")); !errors.Is(err, ErrNoTenant) {
+		t.Fatalf("Expected %v, got %v", ErrNoTenant, err)
+	}
+	if _, err := QueryRows(ctx, db, db.Select("users")); !errors.Is(err, ErrNoTenant) {
+		t.Fatalf("Expected %v, got %v", ErrNoTenant, err)
+	}
+	if _, err := SelectAll[struct {
+		ID int64 `db:"id"`
+	}](ctx, db, db.Select("users")); !errors.Is(err, ErrNoTenant) {
+		t.Fatalf("Expected %v, got %v", ErrNoTenant, err)
+	}
+	bound := &DB{DB: db.DB, RO: db.RO, Builder: db.Builder.(*TenantBuilder).WithContext(WithTenant(ctx, int64(42)))}
+	if _, err := Exec(ctx, bound, bound.Insert("users").SetNames("name").SetValues("test")); err != nil {
+		t.Fatal("Error:", err)
+	}
+	var tenant int64
+	if err := db.QueryRowContext(ctx, `SELECT "tenant_id" FROM "users"`).Scan(&tenant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if tenant != 42 {
+		t.Fatalf("Expected 42, got %d", tenant)
+	}
+}
@@ -1,11 +1,29 @@
 package gosql
 
 // UpdateQuery represents SQL update query.
+//
+// Setters do not modify query and return its modified copy, so shared
+// query can be safely extended.
 type UpdateQuery interface {
 	Query
-	SetWhere(where BoolExpr)
-	SetNames(names ...string)
-	SetValues(values ...any)
+	SetWhere(where BoolExpr) UpdateQuery
+	SetNames(names ...string) UpdateQuery
+	SetValues(values ...any) UpdateQuery
+	// SetRow sets names and values from fields of struct tagged like
+	// `db:"name"`.
+	//
+	// When columns are specified, only these columns are updated.
+	SetRow(row any, columns ...string) UpdateQuery
+	// Clone returns deep copy of query.
+	Clone() UpdateQuery
+	// Table returns name of table.
+	Table() string
+	// Where returns condition of query.
+	Where() BoolExpr
+	// Names returns list of updated columns.
+	Names() []string
+	// Values returns list of assigned values.
+	Values() []Value
 }
 
 type updateQuery struct {
@@ -13,24 +31,61 @@ type updateQuery struct {
 	where  BoolExpr
 	names  []string
 	values []Value
+	// dialect contains dialect of builder that created query.
+	dialect queryDialect
 }
 
-func (q *updateQuery) SetWhere(where BoolExpr) {
-	q.where = where
+func (q *updateQuery) SetWhere(where BoolExpr) UpdateQuery {
+	clone := *q
+	clone.where = where
+	return &clone
 }
 
-func (q *updateQuery) SetNames(names ...string) {
-	q.names = names
+func (q *updateQuery) SetNames(names ...string) UpdateQuery {
+	clone := *q
+	clone.names = names
+	return &clone
 }
 
-func (q *updateQuery) SetValues(values ...any) {
-	q.values = nil
+func (q *updateQuery) SetValues(values ...any) UpdateQuery {
+	clone := *q
+	clone.values = nil
 	for _, val := range values {
-		q.values = append(q.values, wrapValue(val))
+		clone.values = append(clone.values, wrapValue(val))
 	}
+	return &clone
+}
+
+func (q *updateQuery) SetRow(row any, columns ...string) UpdateQuery {
+	names, values := rowValues(row, columns)
+	return q.SetNames(names...).SetValues(values...)
+}
+
+func (q *updateQuery) Clone() UpdateQuery {
+	clone := *q
+	clone.names = append([]string(nil), q.names...)
+	clone.values = append([]Value(nil), q.values...)
+	return &clone
+}
+
+func (q updateQuery) Table() string {
+	return q.table
+}
+
+func (q updateQuery) Where() BoolExpr {
+	return q.where
+}
+
+func (q updateQuery) Names() []string {
+	return append([]string(nil), q.names...)
+}
+
+func (q updateQuery) Values() []Value {
+	return append([]Value(nil), q.values...)
 }
 
 func (q updateQuery) WriteQuery(w Writer) {
+	w = q.dialect.writer(w)
 	w.WriteString("UPDATE ")
 	w.WriteName(q.table)
 	q.writeSet(w)
@@ -44,19 +99,19 @@ func (q updateQuery) writeSet(w Writer) {
 	if len(q.names) != len(q.values) {
 		panic("amount of names and values differs")
 	}
-	w.WriteString(" SET ")
+	writeClause(w, "SET")
 	for i := range q.names {
 		if i > 0 {
 			w.WriteString(", ")
 		}
 		w.WriteName(q.names[i])
 		w.WriteString(" = ")
-		q.values[i].WriteExpr(w)
+		writeColumnExpr(w, q.names[i], q.values[i])
 	}
 }
 
 func (q updateQuery) writeWhere(w Writer) {
-	w.WriteString(" WHERE ")
+	writeClause(w, "WHERE")
 	if q.where == nil {
 		w.WriteString("1 = 1")
 		return